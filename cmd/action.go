@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/njhoffman/i3-tree/cmd/internal"
+	"github.com/njhoffman/i3-tree/pkg/command"
+	"github.com/njhoffman/i3-tree/pkg/i3treeviewer"
+	"github.com/njhoffman/i3-tree/pkg/prune"
+	"github.com/peterbourgon/ff/v3/ffcli"
+	"go.i3wm.org/i3/v4"
+)
+
+// Exit statuses of the action commands
+const (
+	// i3 reported an error for at least one of the commands, or its reply couldn't tell
+	exitCommandFailed = 1
+	// nothing was run: no window matched, several did without --all, i3 couldn't be reached...
+	exitNotRun = 2
+)
+
+// exitError makes Main exit with Code, after logging Err when set
+type exitError struct {
+	Code int
+	Err  error
+}
+
+func (e exitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e exitError) Unwrap() error {
+	return e.Err
+}
+
+var actionHelp = `%[1]s the windows matching a filter expression, the one i3-tree takes to prune the tree
+fields: class, instance, title, mark, workspace (and type, layout, output, urgent, focused, fullscreen, floating)
+the expression can be split over several arguments
+
+the commands are sent to i3 or sway with [con_id=...] criteria
+several windows matching is an error, unless --all is given
+
+EXIT STATUS
+0  every command succeeded
+1  i3 reported an error for at least one of the commands, or replied without a result for each
+2  nothing was run: bad expression, no window or several matching without --all, i3 unreachable
+
+EXAMPLES
+%[2]s`
+
+// newActionCommand creates a command running build for every window it matches
+// build is called with the ids once the flags are parsed
+func newActionCommand(
+	name string,
+	shortHelp string,
+	examples string,
+	fs *flag.FlagSet,
+	build func(id i3.NodeID) (string, error),
+) *ffcli.Command {
+	from := fs.String(
+		"from",
+		string(internal.Auto),
+		"where to fetch the tree from. available: "+fmt.Sprintf("%s", internal.AvailableFetchStrats),
+	)
+	dryRun := fs.Bool("dry-run", false, "print the commands instead of running them")
+	all := fs.Bool("all", false, "act on every matching window, instead of failing when there are several")
+
+	return &ffcli.Command{
+		Name:       name,
+		ShortUsage: "i3-tree " + name + " [flags] <filter expression>",
+		ShortHelp:  shortHelp,
+		LongHelp:   fmt.Sprintf(actionHelp, name, examples),
+		FlagSet:    fs,
+		Exec: func(ctx context.Context, args []string) error {
+			args, err := parseInterspersed(fs, args)
+			if err != nil {
+				return err
+			}
+
+			err = actionExec(os.Stdout, args, actionOptions{
+				From:   *from,
+				DryRun: *dryRun,
+				All:    *all,
+				Build:  build,
+			})
+
+			var exitErr exitError
+			if err != nil && !errors.As(err, &exitErr) {
+				return exitError{Code: exitNotRun, Err: err}
+			}
+			return err
+		},
+	}
+}
+
+func newFocusCommand() *ffcli.Command {
+	return newActionCommand(
+		"focus",
+		"Focus the window matching a filter expression",
+		"i3-tree focus 'class=Slack'\ni3-tree focus --dry-run 'mark=todo'\n",
+		flag.NewFlagSet("focus", flag.ExitOnError),
+		func(id i3.NodeID) (string, error) {
+			return command.Focus(id), nil
+		},
+	)
+}
+
+func newKillCommand() *ffcli.Command {
+	return newActionCommand(
+		"kill",
+		"Close the windows matching a filter expression",
+		"i3-tree kill 'title~/Zoom/'\ni3-tree kill --all 'workspace=9 and class=mpv'\n",
+		flag.NewFlagSet("kill", flag.ExitOnError),
+		func(id i3.NodeID) (string, error) {
+			return command.Kill(id), nil
+		},
+	)
+}
+
+func newMoveCommand() *ffcli.Command {
+	fs := flag.NewFlagSet("move", flag.ExitOnError)
+	to := fs.String("to", "", "workspace to move the windows to, e.g. ws:9")
+
+	return newActionCommand(
+		"move",
+		"Move the windows matching a filter expression to a workspace",
+		"i3-tree move 'class=mpv' --to ws:9\ni3-tree move --all 'title~/jira/i' --to 'ws:2: mail'\n",
+		fs,
+		func(id i3.NodeID) (string, error) {
+			workspace := strings.TrimPrefix(*to, internal.WsPrefix)
+			if workspace == "" {
+				return "", errors.New("move needs a workspace, e.g. --to ws:9")
+			}
+			return command.MoveToWorkspace(id, workspace), nil
+		},
+	)
+}
+
+// parseInterspersed parses the flags given after the positional arguments,
+// e.g. i3-tree move 'class=mpv' --to ws:9
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for len(args) > 0 {
+		switch {
+		case args[0] == "--":
+			return append(positional, args[1:]...), nil
+		case len(args[0]) > 1 && args[0][0] == '-':
+			if err := fs.Parse(args); err != nil {
+				return nil, err
+			}
+			args = fs.Args()
+		default:
+			positional = append(positional, args[0])
+			args = args[1:]
+		}
+	}
+	return positional, nil
+}
+
+type actionOptions struct {
+	From   string
+	DryRun bool
+	All    bool
+	Build  func(id i3.NodeID) (string, error)
+}
+
+func actionExec(w io.Writer, args []string, opts actionOptions) error {
+	fetcher, err := internal.NewFetcher(opts.From)
+	if err != nil {
+		return err
+	}
+
+	// fail early, before looking for the windows
+	var dispatcher command.Dispatcher
+	if !opts.DryRun {
+		if dispatcher, err = internal.NewDispatcher(fetcher); err != nil {
+			return err
+		}
+	}
+
+	return runAction(w, fetcher, dispatcher, args, opts)
+}
+
+// runAction runs the commands built for the windows args match
+// dispatcher is only used without --dry-run
+func runAction(
+	w io.Writer,
+	fetcher i3treeviewer.Fetcher,
+	dispatcher command.Dispatcher,
+	args []string,
+	opts actionOptions,
+) error {
+	if len(args) == 0 {
+		return errors.New("missing filter expression, e.g. 'class=Slack'")
+	}
+	filter, err := prune.NewFilter(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+
+	tree, err := fetcher.Fetch()
+	if err != nil {
+		return err
+	}
+
+	targets := command.Targets(&tree, filter)
+	switch {
+	case len(targets) == 0:
+		return fmt.Errorf("no window matches %s", filter.Expr)
+	case len(targets) > 1 && !opts.All:
+		return ambiguousError(filter.Expr, targets)
+	}
+
+	commands := make([]string, len(targets))
+	for i, t := range targets {
+		if commands[i], err = opts.Build(t.ID); err != nil {
+			return err
+		}
+	}
+
+	if opts.DryRun {
+		for i, t := range targets {
+			fmt.Fprintf(w, "%s  # %s\n", commands[i], t)
+		}
+		return nil
+	}
+
+	return runCommands(w, dispatcher, commands)
+}
+
+// runCommands sends commands in a single message, reporting i3's result for each one
+func runCommands(w io.Writer, dispatcher command.Dispatcher, commands []string) error {
+	results, err := dispatcher.Run(strings.Join(commands, "; "))
+	if err != nil {
+		return err
+	}
+	// the commands were sent, whatever they did is unknown
+	if len(results) != len(commands) {
+		return exitError{
+			Code: exitCommandFailed,
+			Err:  fmt.Errorf("the commands were sent, but i3 replied with %d results for %d of them", len(results), len(commands)),
+		}
+	}
+
+	failed := 0
+	for i, r := range results {
+		if r.Success {
+			fmt.Fprintf(w, "%s: ok\n", commands[i])
+			continue
+		}
+		failed++
+		fmt.Fprintf(w, "%s: %s\n", commands[i], r.Error)
+	}
+
+	if failed > 0 {
+		return exitError{
+			Code: exitCommandFailed,
+			Err:  fmt.Errorf("%d of %d commands failed", failed, len(commands)),
+		}
+	}
+	return nil
+}
+
+func ambiguousError(expr string, targets []command.Target) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s matches %d windows, use --all to act on every one of them:", expr, len(targets))
+	for _, t := range targets {
+		fmt.Fprintf(&b, "\n  %s %s", command.Criteria(t.ID), t)
+	}
+	return errors.New(b.String())
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/command"
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/stretchr/testify/assert"
+	"go.i3wm.org/i3/v4"
+)
+
+func kill(id i3.NodeID) (string, error) {
+	return command.Kill(id), nil
+}
+
+func TestRunAction(t *testing.T) {
+	var out bytes.Buffer
+	rec := &command.Recorder{}
+
+	err := runAction(&out, fetch.FromFake{}, rec, []string{"title~/slack/i"}, actionOptions{Build: kill})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"[con_id=15] kill"}, rec.Commands)
+	assert.Equal(t, "[con_id=15] kill: ok\n", out.String())
+}
+
+func TestRunActionAll(t *testing.T) {
+	var out bytes.Buffer
+	rec := &command.Recorder{}
+
+	// the expression can be split over several arguments
+	args := []string{"title~VLC", "and", "not", "workspace=3"}
+
+	err := runAction(&out, fetch.FromFake{}, rec, args, actionOptions{Build: kill})
+	assert.EqualError(t, err, "title~VLC and not workspace=3 matches 2 windows, use --all to act on every one of them:\n"+
+		"  [con_id=14] VLC media player on workspace 4\n"+
+		"  [con_id=26] VLC media player on workspace 6")
+	assert.Empty(t, rec.Commands)
+
+	err = runAction(&out, fetch.FromFake{}, rec, args, actionOptions{Build: kill, All: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"[con_id=14] kill; [con_id=26] kill"}, rec.Commands)
+	assert.Equal(t, "[con_id=14] kill: ok\n[con_id=26] kill: ok\n", out.String())
+}
+
+func TestRunActionDryRun(t *testing.T) {
+	var out bytes.Buffer
+
+	err := runAction(&out, fetch.FromFake{}, nil, []string{"title~VLC"}, actionOptions{
+		Build:  kill,
+		All:    true,
+		DryRun: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "[con_id=11] kill  # VLC media player on workspace 3\n"+
+		"[con_id=14] kill  # VLC media player on workspace 4\n"+
+		"[con_id=26] kill  # VLC media player on workspace 6\n", out.String())
+}
+
+func TestRunActionFailures(t *testing.T) {
+	var out bytes.Buffer
+	rec := &command.Recorder{Results: []i3.CommandResult{
+		{Success: true},
+		{Success: false, Error: "No window matches given criteria"},
+	}}
+
+	// a result is expected for every command
+	// and the commands were sent without one
+	err := runAction(&out, fetch.FromFake{}, rec, []string{"title~/bash/"}, actionOptions{Build: kill, All: true})
+	var exitErr exitError
+	assert.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, exitCommandFailed, exitErr.Code)
+	assert.EqualError(t, err, "the commands were sent, but i3 replied with 2 results for 6 of them")
+	assert.Len(t, rec.Commands, 1)
+
+	out.Reset()
+	err = runAction(&out, fetch.FromFake{}, rec, []string{"workspace=3"}, actionOptions{Build: kill, All: true})
+	exitErr = exitError{}
+	assert.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, exitCommandFailed, exitErr.Code)
+	assert.EqualError(t, err, "1 of 2 commands failed")
+	assert.Equal(t, "[con_id=10] kill: ok\n[con_id=11] kill: No window matches given criteria\n", out.String())
+}
+
+func TestRunActionErrors(t *testing.T) {
+	cases := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"no expression", nil, "missing filter expression, e.g. 'class=Slack'"},
+		{"no match", []string{"class=Zoom"}, "no window matches class=Zoom"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &command.Recorder{}
+			err := runAction(&bytes.Buffer{}, fetch.FromFake{}, rec, tt.args, actionOptions{Build: kill})
+
+			assert.EqualError(t, err, tt.wantErr)
+			assert.Empty(t, rec.Commands)
+		})
+	}
+
+	err := runAction(&bytes.Buffer{}, fetch.FromFake{}, nil, []string{"class=="}, actionOptions{Build: kill})
+	assert.Error(t, err)
+
+	rec := &command.Recorder{Err: errors.New("connection refused")}
+	err = runAction(&bytes.Buffer{}, fetch.FromFake{}, rec, []string{"title=Slack"}, actionOptions{Build: kill})
+	assert.EqualError(t, err, "connection refused")
+}
+
+func TestParseInterspersed(t *testing.T) {
+	fs := flag.NewFlagSet("move", flag.ContinueOnError)
+	to := fs.String("to", "", "")
+	all := fs.Bool("all", false, "")
+
+	args, err := parseInterspersed(fs, []string{"class=mpv", "--to", "ws:9", "or", "--all", "class=vlc"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"class=mpv", "or", "class=vlc"}, args)
+	assert.Equal(t, "ws:9", *to)
+	assert.True(t, *all)
+
+	args, err = parseInterspersed(fs, []string{"title=x", "--", "--to"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"title=x", "--to"}, args)
+}
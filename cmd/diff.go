@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/njhoffman/i3-tree/cmd/internal"
+	"github.com/njhoffman/i3-tree/pkg/diff"
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/peterbourgon/ff/v3/ffcli"
+)
+
+var diffHelp = `diff compares two trees dumped with i3-msg -t get_tree, node by node
+nodes are matched by id, or by window id when i3 restarted in between
+
+every change is one of: added, removed, moved (to another parent), reordered (among its siblings),
+renamed, layout, rect (position or size) and flag (focused, urgent, fullscreen or floating)
+
+EXAMPLES
+# what a command changed
+i3-msg -t get_tree > before.json
+i3-msg 'move container to workspace 3'
+i3-msg -t get_tree | i3-tree diff before.json -
+
+# the changes as JSON, e.g. for jq
+i3-tree diff --render=json before.json after.json | jq '.[] | select(.kind == "moved")'
+`
+
+// Render strategies of the diff command
+var diffRenderStrats = []string{"console", "no-color", "json", "json-compact"}
+
+func newDiffCommand() *ffcli.Command {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	render := fs.String(
+		"render",
+		"console",
+		"how to render the changes. available: "+fmt.Sprintf("%s", diffRenderStrats),
+	)
+
+	return &ffcli.Command{
+		Name:       "diff",
+		ShortUsage: "i3-tree diff [flags] <old.json> <new.json>",
+		ShortHelp:  "List the changes between two trees",
+		LongHelp:   diffHelp,
+		FlagSet:    fs,
+		Exec: func(ctx context.Context, args []string) error {
+			return diffExec(args, *render)
+		},
+	}
+}
+
+func diffExec(args []string, render string) error {
+	switch render {
+	case "console", "no-color", "json", "json-compact":
+	default:
+		return internal.BadStratError{StratName: render}
+	}
+
+	if len(args) != 2 {
+		return errors.New("diff needs two trees, e.g. i3-tree diff old.json new.json (- reads stdin)")
+	}
+	if args[0] == fetch.Stdin && args[1] == fetch.Stdin {
+		return errors.New("only one of the trees can be read from stdin")
+	}
+
+	from, err := fetch.FromFile{Path: args[0]}.Fetch()
+	if err != nil {
+		return err
+	}
+	to, err := fetch.FromFile{Path: args[1]}.Fetch()
+	if err != nil {
+		return err
+	}
+
+	changes := diff.Trees(&from, &to)
+
+	if render == "json" || render == "json-compact" {
+		return diff.PrintJSON(os.Stdout, changes, render == "json")
+	}
+
+	diff.Print(os.Stdout, changes, aurora.NewAurora(render == "console"))
+	return nil
+}
@@ -0,0 +1,24 @@
+package internal
+
+import (
+	"errors"
+
+	"github.com/njhoffman/i3-tree/pkg/command"
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/njhoffman/i3-tree/pkg/i3treeviewer"
+)
+
+// ErrNoWindowManager is returned when commands are asked for on a tree no window manager is behind
+var ErrNoWindowManager = errors.New("commands can only be run on i3 or sway, not on a mock tree or a file")
+
+// NewDispatcher returns what runs commands on the window manager the tree is fetched from
+func NewDispatcher(fetcher i3treeviewer.Fetcher) (command.Dispatcher, error) {
+	switch f := fetcher.(type) {
+	case fetch.FromI3:
+		return command.I3{}, nil
+	case fetch.FromSway:
+		return command.Sway{SocketPath: f.Socket()}, nil
+	default:
+		return nil, ErrNoWindowManager
+	}
+}
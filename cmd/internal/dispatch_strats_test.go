@@ -0,0 +1,34 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/njhoffman/i3-tree/cmd/internal"
+	"github.com/njhoffman/i3-tree/pkg/command"
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/njhoffman/i3-tree/pkg/i3treeviewer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDispatcher(t *testing.T) {
+	cases := []struct {
+		name    string
+		fetcher i3treeviewer.Fetcher
+		want    command.Dispatcher
+		wantErr error
+	}{
+		{"i3", fetch.FromI3{}, command.I3{}, nil},
+		{"sway", fetch.FromSway{SocketPath: "/run/sway.sock"}, command.Sway{SocketPath: "/run/sway.sock"}, nil},
+		{"mock", fetch.FromFake{}, nil, internal.ErrNoWindowManager},
+		{"file", fetch.FromFile{Path: "tree.json"}, nil, internal.ErrNoWindowManager},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := internal.NewDispatcher(tt.fetcher)
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, gotErr)
+		})
+	}
+}
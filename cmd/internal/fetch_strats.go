@@ -1,6 +1,9 @@
 package internal
 
 import (
+	"os"
+	"strings"
+
 	"github.com/njhoffman/i3-tree/pkg/fetch"
 	"github.com/njhoffman/i3-tree/pkg/i3treeviewer"
 )
@@ -9,22 +12,67 @@ import (
 type FetchStratName string
 
 var (
-	FromI3 FetchStratName = "i3"
-	Mock   FetchStratName = "mock"
+	// Auto uses sway when $SWAYSOCK is set, i3 otherwise
+	Auto     FetchStratName = "auto"
+	FromI3   FetchStratName = "i3"
+	FromSway FetchStratName = "sway"
+	Mock     FetchStratName = "mock"
+	// File reads a GET_TREE dump, used as file:/path/to/tree.json
+	File FetchStratName = "file"
+	// Stdin reads a GET_TREE dump from stdin
+	Stdin FetchStratName = fetch.Stdin
 
 	AvailableFetchStrats = []FetchStratName{
+		Auto,
 		FromI3,
+		FromSway,
 		Mock,
+		File + ":<path>",
+		Stdin,
 	}
 )
 
 func NewFetcher(strat string) (i3treeviewer.Fetcher, error) {
+	if path := strings.TrimPrefix(strat, string(File)+":"); path != strat {
+		if path == "" {
+			return nil, BadStratError{strat}
+		}
+		return fetch.FromFile{Path: path}, nil
+	}
+
 	switch FetchStratName(strat) {
+	case Auto:
+		if os.Getenv(fetch.SwaySockEnv) != "" {
+			return fetch.FromSway{}, nil
+		}
+		return fetch.FromI3{}, nil
 	case FromI3:
 		return fetch.FromI3{}, nil
+	case FromSway:
+		return fetch.FromSway{}, nil
 	case Mock:
 		return fetch.FromFake{}, nil
+	case Stdin:
+		return fetch.FromFile{Path: fetch.Stdin}, nil
 	default:
 		return nil, BadStratError{strat}
 	}
 }
+
+// WithExtras makes fetcher keep what it knows about the nodes besides what go-i3 decodes in extras
+// fetchers knowing nothing more are returned as they are
+func WithExtras(fetcher i3treeviewer.Fetcher, extras fetch.Extras) i3treeviewer.Fetcher {
+	switch f := fetcher.(type) {
+	case fetch.FromI3:
+		f.Extras = extras
+		return f
+	case fetch.FromSway:
+		f.Extras = extras
+		return f
+	case fetch.FromFile:
+		f.Extras = extras
+		return f
+	default:
+		return fetcher
+	}
+}
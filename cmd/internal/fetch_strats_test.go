@@ -1,6 +1,7 @@
 package internal_test
 
 import (
+	"os"
 	"testing"
 
 	"github.com/njhoffman/i3-tree/cmd/internal"
@@ -16,7 +17,13 @@ func TestNewFetcher(t *testing.T) {
 		wantErr   error
 	}{
 		{"i3", fetch.FromI3{}, nil},
+		{"sway", fetch.FromSway{}, nil},
 		{"mock", fetch.FromFake{}, nil},
+		{"file:/tmp/tree.json", fetch.FromFile{Path: "/tmp/tree.json"}, nil},
+		{"file:-", fetch.FromFile{Path: "-"}, nil},
+		{"-", fetch.FromFile{Path: "-"}, nil},
+		{"file:", nil, internal.BadStratError{"file:"}},
+		{"file", nil, internal.BadStratError{"file"}},
 		{"unknown", nil, internal.BadStratError{"unknown"}},
 	}
 
@@ -29,3 +36,34 @@ func TestNewFetcher(t *testing.T) {
 		})
 	}
 }
+
+func TestNewFetcherAuto(t *testing.T) {
+	originalSock, wasSet := os.LookupEnv(fetch.SwaySockEnv)
+	defer func() {
+		if wasSet {
+			os.Setenv(fetch.SwaySockEnv, originalSock)
+		} else {
+			os.Unsetenv(fetch.SwaySockEnv)
+		}
+	}()
+
+	os.Unsetenv(fetch.SwaySockEnv)
+	got, gotErr := internal.NewFetcher("auto")
+	assert.Nil(t, gotErr)
+	assert.Equal(t, fetch.FromI3{}, got)
+
+	os.Setenv(fetch.SwaySockEnv, "/run/user/1000/sway-ipc.sock")
+	got, gotErr = internal.NewFetcher("auto")
+	assert.Nil(t, gotErr)
+	assert.Equal(t, fetch.FromSway{}, got)
+}
+
+func TestWithExtras(t *testing.T) {
+	extras := fetch.Extras{}
+
+	assert.Equal(t, fetch.FromSway{SocketPath: "/run/sway.sock", Extras: extras},
+		internal.WithExtras(fetch.FromSway{SocketPath: "/run/sway.sock"}, extras))
+	assert.Equal(t, fetch.FromI3{Extras: extras}, internal.WithExtras(fetch.FromI3{}, extras))
+	assert.Equal(t, fetch.FromFile{Path: "-", Extras: extras}, internal.WithExtras(fetch.FromFile{Path: "-"}, extras))
+	assert.Equal(t, fetch.FromFake{}, internal.WithExtras(fetch.FromFake{}, extras))
+}
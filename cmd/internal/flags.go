@@ -0,0 +1,62 @@
+package internal
+
+import "strings"
+
+// OptionalString is a flag value that can be given without a value,
+// e.g. both -W and -W=/tmp/changes.log are valid
+// the flag package leaves the value of -W /tmp/changes.log as an argument, see TakePath
+type OptionalString struct {
+	// Given tells if the flag was used, with or without a value
+	Given bool
+	Value string
+}
+
+func (o *OptionalString) String() string {
+	if o == nil {
+		return ""
+	}
+	return o.Value
+}
+
+func (o *OptionalString) Set(s string) error {
+	switch s {
+	case "true":
+		o.Given = true
+	case "false":
+		o.Given = false
+	default:
+		o.Given = true
+		o.Value = s
+	}
+	return nil
+}
+
+// IsBoolFlag lets the flag package accept the flag without a value
+func (o *OptionalString) IsBoolFlag() bool {
+	return true
+}
+
+// TakePath uses the first argument as the value of a flag given without one,
+// when it looks like a path: starting with /, ./ or ../
+// workspace regular expressions, e.g. /^\d:/ or /mail/i, are left as arguments
+// it returns the arguments left
+func (o *OptionalString) TakePath(args []string) []string {
+	if !o.Given || o.Value != "" || len(args) == 0 || !isPath(args[0]) {
+		return args
+	}
+
+	o.Value = args[0]
+	return args[1:]
+}
+
+func isPath(arg string) bool {
+	if strings.HasSuffix(arg, "/") || strings.HasSuffix(arg, "/i") {
+		return false
+	}
+	for _, prefix := range []string{"/", "./", "../"} {
+		if strings.HasPrefix(arg, prefix) {
+			return true
+		}
+	}
+	return false
+}
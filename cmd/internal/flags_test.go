@@ -0,0 +1,47 @@
+package internal_test
+
+import (
+	"flag"
+	"io/ioutil"
+	"testing"
+
+	"github.com/njhoffman/i3-tree/cmd/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionalString(t *testing.T) {
+	cases := []struct {
+		name      string
+		args      []string
+		wantGiven bool
+		wantValue string
+		wantArgs  []string
+	}{
+		{"not given", []string{"all"}, false, "", []string{"all"}},
+		{"without value", []string{"-W", "all"}, true, "", []string{"all"}},
+		{"long without value", []string{"--watch-log", "all"}, true, "", []string{"all"}},
+		{"with value", []string{"-W=/tmp/changes.log", "all"}, true, "/tmp/changes.log", []string{"all"}},
+		{"value after the flag", []string{"-W", "/tmp/changes.log", "all"}, true, "/tmp/changes.log", []string{"all"}},
+		{"relative value after the flag", []string{"-W", "./changes.log"}, true, "./changes.log", []string{}},
+		{"workspace regexp after the flag", []string{"-W", `/^\d:/`}, true, "", []string{`/^\d:/`}},
+		{"workspace after the flag", []string{"-W", "3:mail"}, true, "", []string{"3:mail"}},
+		{"path without the flag", []string{"/tmp/changes.log"}, false, "", []string{"/tmp/changes.log"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var o internal.OptionalString
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(ioutil.Discard)
+			fs.Var(&o, "watch-log", "")
+			fs.Var(&o, "W", "")
+
+			assert.NoError(t, fs.Parse(tt.args))
+			args := o.TakePath(fs.Args())
+
+			assert.Equal(t, tt.wantGiven, o.Given)
+			assert.Equal(t, tt.wantValue, o.Value)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
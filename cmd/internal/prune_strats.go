@@ -1,24 +1,169 @@
 package internal
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/njhoffman/i3-tree/pkg/fetch"
 	"github.com/njhoffman/i3-tree/pkg/i3treeviewer"
 	"github.com/njhoffman/i3-tree/pkg/prune"
 )
 
+// Prefix of the workspace strategy, for names that would be understood otherwise
+// e.g. ws:all or ws:urgent
+const WsPrefix = "ws:"
+
+// Prefix of the output strategy, e.g. output:DP-2, output:HDMI-* or output:primary
+const OutputPrefix = "output:"
+
+// Prefix of the depth stage, e.g. +depth:2
+const DepthPrefix = "depth:"
+
+// Prefix of the arguments adding a stage to the prune chain
+const StagePrefix = "+"
+
+// PrunerOptions are settings only some strategies use
+type PrunerOptions struct {
+	// Fetcher tells pruners what the tree doesn't, when it can
+	Fetcher i3treeviewer.Fetcher
+	// IncludeScratch keeps i3's internal scratchpad workspace in all
+	IncludeScratch bool
+	// Extras tell scratch the windows shown from the scratchpad, see WithExtras
+	Extras fetch.Extras
+	// Simplify collapses redundant split containers after every other stage
+	Simplify bool
+	// Match keeps what it matches after the other stages
+	// all the non empty workspaces are searched, unless an argument picks others
+	Match *prune.Match
+}
+
 // NewPruner decides which prune strategy to use
 // Based on the flag name
-func NewPruner(arg string) (i3treeviewer.Pruner, error) {
+func NewPruner(arg string, opts PrunerOptions) (i3treeviewer.Pruner, error) {
 	switch arg {
 	case "":
 		return &prune.FocusedWs{}, nil
 
 	case "all":
-		return &prune.NonEmptyWs{}, nil
+		return &prune.NonEmptyWs{IncludeScratch: opts.IncludeScratch}, nil
 
 	case "raw":
 		return &prune.NoOp{}, nil
 
+	case "visible":
+		lister, _ := opts.Fetcher.(prune.OutputLister)
+		return &prune.Visible{Lister: lister}, nil
+
+	case "scratch":
+		return &prune.Scratch{Extras: opts.Extras}, nil
+	}
+
+	if strings.HasPrefix(arg, WsPrefix) {
+		return &prune.Ws{WsIndex: strings.TrimPrefix(arg, WsPrefix)}, nil
+	}
+
+	if strings.HasPrefix(arg, OutputPrefix) {
+		pattern := strings.TrimPrefix(arg, OutputPrefix)
+		if pattern == "" {
+			return nil, BadStratError{arg}
+		}
+
+		lister, _ := opts.Fetcher.(prune.OutputLister)
+		o, err := prune.NewOutput(pattern, lister)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", arg, err)
+		}
+		return o, nil
+	}
+
+	// e.g. i3-tree 'class=Firefox or urgent'
+	if prune.IsFilter(arg) {
+		f, err := prune.NewFilter(arg)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	}
+
+	return &prune.Ws{WsIndex: arg}, nil
+}
+
+// NewPrunerChain creates a pruner from all the positional arguments
+// The first one is parsed by NewPruner, unless it's a stage
+// and every following "+stage" adds a pruner applied to what the previous ones kept
+// e.g. i3-tree ws:3 +floating +depth:2
+func NewPrunerChain(args []string, opts PrunerOptions) (i3treeviewer.Pruner, error) {
+	base := ""
+	stages := args
+	if len(args) > 0 && !strings.HasPrefix(args[0], StagePrefix) {
+		base = args[0]
+		stages = args[1:]
+	} else if opts.Match != nil {
+		base = "all"
+	}
+
+	first, err := NewPruner(base, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(stages) == 0 && opts.Match == nil && !opts.Simplify {
+		return first, nil
+	}
+
+	chain := &prune.Chain{Stages: []i3treeviewer.Pruner{first}}
+	for _, arg := range stages {
+		if !strings.HasPrefix(arg, StagePrefix) {
+			return nil, fmt.Errorf("unexpected argument %q, prune stages start with %s", arg, StagePrefix)
+		}
+
+		s, err := newStage(strings.TrimPrefix(arg, StagePrefix), opts)
+		if err != nil {
+			return nil, err
+		}
+		chain.Stages = append(chain.Stages, s)
+	}
+
+	if opts.Match != nil {
+		chain.Stages = append(chain.Stages, opts.Match)
+	}
+	if opts.Simplify {
+		chain.Stages = append(chain.Stages, &prune.Simplify{})
+	}
+
+	return chain, nil
+}
+
+// newStage parses a prune chain stage
+// on top of the NewPruner arguments, focused, simplify and depth:N are available,
+// and the boolean fields of filters can be used alone, e.g. +floating
+func newStage(arg string, opts PrunerOptions) (i3treeviewer.Pruner, error) {
+	switch {
+	case arg == "":
+		return nil, BadStratError{StagePrefix}
+
+	case arg == "focused":
+		return &prune.FocusedWs{}, nil
+
+	case arg == "simplify":
+		return &prune.Simplify{}, nil
+
+	case strings.HasPrefix(arg, DepthPrefix):
+		max, err := strconv.Atoi(strings.TrimPrefix(arg, DepthPrefix))
+		if err != nil || max < 0 {
+			return nil, BadStratError{StagePrefix + arg}
+		}
+		return &prune.Depth{Max: max}, nil
+
+	case prune.IsStageFilter(arg):
+		// e.g. +floating, which would be a workspace name as the first argument
+		f, err := prune.NewFilter(arg)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+
 	default:
-		return &prune.Ws{WsIndex: arg}, nil
+		return NewPruner(arg, opts)
 	}
 }
@@ -1,12 +1,15 @@
 package internal_test
 
 import (
+	"path"
 	"testing"
 
 	"github.com/njhoffman/i3-tree/cmd/internal"
+	"github.com/njhoffman/i3-tree/pkg/fetch"
 	"github.com/njhoffman/i3-tree/pkg/i3treeviewer"
 	"github.com/njhoffman/i3-tree/pkg/prune"
 	"github.com/stretchr/testify/assert"
+	"go.i3wm.org/i3/v4"
 )
 
 func TestNewPruner(t *testing.T) {
@@ -19,14 +22,220 @@ func TestNewPruner(t *testing.T) {
 		{"", &prune.FocusedWs{}, nil},
 		{"raw", &prune.NoOp{}, nil},
 		{"5", &prune.Ws{WsIndex: "5"}, nil},
+		{"3:mail", &prune.Ws{WsIndex: "3:mail"}, nil},
+		{"visible", &prune.Visible{}, nil},
+		{"scratch", &prune.Scratch{}, nil},
+		{"ws:visible", &prune.Ws{WsIndex: "visible"}, nil},
+		{"class=Firefox or urgent", mustFilter(t, "class=Firefox or urgent"), nil},
+		{"not floating", mustFilter(t, "not floating"), nil},
+		{"ws:urgent", &prune.Ws{WsIndex: "urgent"}, nil},
+		{"urgent", &prune.Ws{WsIndex: "urgent"}, nil},
+		{"output 2", &prune.Ws{WsIndex: "output 2"}, nil},
+		{"output:DP-2", &prune.Output{Pattern: "DP-2"}, nil},
+		{"output:HDMI-*", &prune.Output{Pattern: "HDMI-*"}, nil},
+		{"output:", nil, internal.BadStratError{"output:"}},
+		{"output=DP-2", mustFilter(t, "output=DP-2"), nil},
 	}
 
 	for _, tt := range cases {
 		t.Run(tt.arg, func(t *testing.T) {
-			got, gotErr := internal.NewPruner(tt.arg)
+			got, gotErr := internal.NewPruner(tt.arg, internal.PrunerOptions{})
 
 			assert.Equal(t, tt.want, got)
 			assert.Equal(t, tt.wantErr, gotErr)
 		})
 	}
 }
+
+func TestNewPrunerOutputLister(t *testing.T) {
+	got, err := internal.NewPruner("output:primary", internal.PrunerOptions{Fetcher: fetch.FromFake{}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &prune.Output{Pattern: "primary", Lister: fetch.FromFake{}}, got)
+}
+
+func TestNewPrunerIncludeScratch(t *testing.T) {
+	got, err := internal.NewPruner("all", internal.PrunerOptions{IncludeScratch: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &prune.NonEmptyWs{IncludeScratch: true}, got)
+}
+
+func TestNewPrunerBadOutputPattern(t *testing.T) {
+	_, err := internal.NewPruner("output:[", internal.PrunerOptions{})
+
+	assert.EqualError(t, err, "output:[: syntax error in pattern")
+	assert.ErrorIs(t, err, path.ErrBadPattern)
+}
+
+func TestNewPrunerBadFilter(t *testing.T) {
+	_, err := internal.NewPruner("class=Firefox or", internal.PrunerOptions{})
+
+	assert.IsType(t, prune.FilterError{}, err)
+}
+
+func TestNewPrunerChain(t *testing.T) {
+	cases := []struct {
+		name    string
+		args    []string
+		want    i3treeviewer.Pruner
+		wantErr error
+	}{
+		{"no args", nil, &prune.FocusedWs{}, nil},
+		{"single arg", []string{"all"}, &prune.NonEmptyWs{}, nil},
+		{
+			"stages",
+			[]string{"ws:3", "+floating", "+depth:2"},
+			&prune.Chain{Stages: []i3treeviewer.Pruner{
+				&prune.Ws{WsIndex: "3"},
+				mustFilter(t, "floating"),
+				&prune.Depth{Max: 2},
+			}},
+			nil,
+		},
+		{
+			"filter stages",
+			[]string{"all", "+urgent", "+fullscreen", "+floating=false", "+not urgent"},
+			&prune.Chain{Stages: []i3treeviewer.Pruner{
+				&prune.NonEmptyWs{},
+				mustFilter(t, "urgent"),
+				mustFilter(t, "fullscreen"),
+				mustFilter(t, "floating=false"),
+				mustFilter(t, "not urgent"),
+			}},
+			nil,
+		},
+		{
+			"workspace stage",
+			[]string{"all", "+chat"},
+			&prune.Chain{Stages: []i3treeviewer.Pruner{
+				&prune.NonEmptyWs{},
+				&prune.Ws{WsIndex: "chat"},
+			}},
+			nil,
+		},
+		{
+			"stages only",
+			[]string{"+focused", "+raw"},
+			&prune.Chain{Stages: []i3treeviewer.Pruner{
+				&prune.FocusedWs{},
+				&prune.FocusedWs{},
+				&prune.NoOp{},
+			}},
+			nil,
+		},
+		{
+			"simplify stage",
+			[]string{"all", "+simplify"},
+			&prune.Chain{Stages: []i3treeviewer.Pruner{
+				&prune.NonEmptyWs{},
+				&prune.Simplify{},
+			}},
+			nil,
+		},
+		{"bad depth", []string{"all", "+depth:x"}, nil, internal.BadStratError{"+depth:x"}},
+		{"negative depth", []string{"all", "+depth:-1"}, nil, internal.BadStratError{"+depth:-1"}},
+		{"empty stage", []string{"all", "+"}, nil, internal.BadStratError{"+"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := internal.NewPrunerChain(tt.args, internal.PrunerOptions{})
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, gotErr)
+		})
+	}
+}
+
+func TestNewPrunerChainSimplify(t *testing.T) {
+	got, err := internal.NewPrunerChain([]string{"ws:3", "+floating"}, internal.PrunerOptions{Simplify: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &prune.Chain{Stages: []i3treeviewer.Pruner{
+		&prune.Ws{WsIndex: "3"},
+		mustFilter(t, "floating"),
+		&prune.Simplify{},
+	}}, got)
+
+	got, err = internal.NewPrunerChain(nil, internal.PrunerOptions{Simplify: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &prune.Chain{Stages: []i3treeviewer.Pruner{
+		&prune.FocusedWs{},
+		&prune.Simplify{},
+	}}, got)
+}
+
+func TestNewPrunerChainMatch(t *testing.T) {
+	match, err := prune.NewMatch("class=Slack", 1)
+	assert.NoError(t, err)
+
+	got, err := internal.NewPrunerChain(nil, internal.PrunerOptions{Match: match, Simplify: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &prune.Chain{Stages: []i3treeviewer.Pruner{
+		&prune.NonEmptyWs{},
+		match,
+		&prune.Simplify{},
+	}}, got)
+
+	got, err = internal.NewPrunerChain([]string{"ws:3"}, internal.PrunerOptions{Match: match})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &prune.Chain{Stages: []i3treeviewer.Pruner{
+		&prune.Ws{WsIndex: "3"},
+		match,
+	}}, got)
+}
+
+func TestNewPrunerChainBadArgs(t *testing.T) {
+	_, err := internal.NewPrunerChain([]string{"all", "floating"}, internal.PrunerOptions{})
+	assert.EqualError(t, err, `unexpected argument "floating", prune stages start with +`)
+
+	_, err = internal.NewPrunerChain([]string{"all", "+class="}, internal.PrunerOptions{})
+	assert.IsType(t, prune.FilterError{}, err)
+}
+
+// the example of the help: ws:3 +floating +depth:2
+func TestNewPrunerChainFloatingStage(t *testing.T) {
+	chain, err := internal.NewPrunerChain([]string{"ws:3", "+floating", "+depth:2"}, internal.PrunerOptions{})
+	assert.NoError(t, err)
+
+	tree := i3.Tree{Root: &i3.Node{
+		ID:   1,
+		Type: "root",
+		Nodes: []*i3.Node{{
+			ID:   2,
+			Name: "DP-1",
+			Type: "output",
+			Nodes: []*i3.Node{{
+				ID:    3,
+				Name:  "3",
+				Type:  "workspace",
+				Nodes: []*i3.Node{{ID: 4, Name: "vim", Type: "con"}},
+				FloatingNodes: []*i3.Node{{
+					ID:    5,
+					Type:  "floating_con",
+					Nodes: []*i3.Node{{ID: 6, Name: "mpv", Type: "con"}},
+				}},
+			}},
+		}},
+	}}
+
+	got, err := i3treeviewer.TryPrune(chain, &tree)
+
+	assert.NoError(t, err)
+	ws := got.Root.Nodes[0].Nodes[0]
+	assert.Empty(t, ws.Nodes)
+	assert.Len(t, ws.FloatingNodes, 1)
+	assert.Equal(t, "mpv", ws.FloatingNodes[0].Nodes[0].Name)
+}
+
+func mustFilter(t *testing.T, expr string) *prune.Filter {
+	f, err := prune.NewFilter(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
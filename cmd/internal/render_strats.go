@@ -1,9 +1,11 @@
 package internal
 
 import (
+	"io"
 	"os"
 
 	"github.com/njhoffman/i3-tree/pkg/config"
+	"github.com/njhoffman/i3-tree/pkg/fetch"
 	"github.com/njhoffman/i3-tree/pkg/i3treeviewer"
 	"github.com/njhoffman/i3-tree/pkg/render"
 )
@@ -15,18 +17,66 @@ var (
 	ConsoleStrat RendererStrat = "console"
 	// Console, but no color strategy
 	ConsoleNoColorStrat RendererStrat = "no-color"
+	// Indented JSON strategy
+	JSONStrat RendererStrat = "json"
+	// Single line JSON strategy
+	JSONCompactStrat RendererStrat = "json-compact"
+	// Graphviz strategy
+	DotStrat RendererStrat = "dot"
+	// Mermaid diagram strategy
+	MermaidStrat RendererStrat = "mermaid"
+	// Standalone HTML page strategy
+	HTMLStrat RendererStrat = "html"
+	// SVG image of the colored console output strategy
+	SVGStrat RendererStrat = "svg"
+	// Boxes scaled from the container rects strategy
+	MapStrat RendererStrat = "map"
+	// User defined text/template strategy
+	TemplateStrat RendererStrat = "template"
+	// User defined text/template, but no color strategy
+	TemplateNoColorStrat RendererStrat = "template-no-color"
 
 	// List of all available render strategies
 	AvailableRendererStrats = []RendererStrat{
 		ConsoleStrat,
 		ConsoleNoColorStrat,
+		JSONStrat,
+		JSONCompactStrat,
+		DotStrat,
+		MermaidStrat,
+		HTMLStrat,
+		SVGStrat,
+		MapStrat,
+		TemplateStrat,
+		TemplateNoColorStrat,
 	}
 )
 
+// RendererOptions are settings only some strategies use
+type RendererOptions struct {
+	// Writer is where the tree is rendered, os.Stdout when nil
+	Writer io.Writer
+
+	// Template is the text/template of TemplateStrat and TemplateNoColorStrat
+	// render.DefaultTemplate is used when empty
+	Template string
+
+	// Depth is the number of levels the console strategies render
+	// deeper subtrees are summarized, 0 renders everything
+	Depth int
+
+	// Match finds the nodes the console strategies highlight
+	Match render.Matcher
+
+	// Extras are shown by the console, JSON and template strategies
+	// they are filled in by the fetcher, see WithExtras
+	Extras fetch.Extras
+}
+
 // NewRenderer creates a i3treeviewer.Renderer
 // Based on a strategy
 // Otherwise it fails with BadStratError
-func NewRenderer(strat string) (i3treeviewer.Renderer, error) {
+func NewRenderer(strat string, opts RendererOptions) (i3treeviewer.Renderer, error) {
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -34,12 +84,69 @@ func NewRenderer(strat string) (i3treeviewer.Renderer, error) {
 		cfg = config.DefaultConfig()
 	}
 
+	w := opts.Writer
+	if w == nil {
+		w = os.Stdout
+	}
+
 	switch RendererStrat(strat) {
 	case ConsoleStrat:
-		return render.NewColoredConsoleWithConfig(os.Stdout, cfg), nil
+		r := render.NewColoredConsoleWithConfig(w, cfg)
+		r.MaxDepth = opts.Depth
+		r.Match = opts.Match
+		r.Extras = opts.Extras
+		return r, nil
 
 	case ConsoleNoColorStrat:
-		return render.NewMonochromaticConsoleWithConfig(os.Stdout, cfg), nil
+		r := render.NewMonochromaticConsoleWithConfig(w, cfg)
+		r.MaxDepth = opts.Depth
+		r.Match = opts.Match
+		r.Extras = opts.Extras
+		return r, nil
+
+	case JSONStrat:
+		r := render.NewJSON(w)
+		r.Extras = opts.Extras
+		return r, nil
+
+	case JSONCompactStrat:
+		r := render.NewCompactJSON(w)
+		r.Extras = opts.Extras
+		return r, nil
+
+	case DotStrat:
+		return render.NewDotWithConfig(w, cfg), nil
+
+	case MermaidStrat:
+		return render.NewMermaidWithConfig(w, cfg), nil
+
+	case HTMLStrat:
+		return render.NewHTMLWithConfig(w, cfg), nil
+
+	case SVGStrat:
+		r := render.NewSVGWithConfig(w, cfg)
+		r.MaxDepth = opts.Depth
+		r.Match = opts.Match
+		return r, nil
+
+	case MapStrat:
+		return render.NewMapWithConfig(w, cfg), nil
+
+	case TemplateStrat:
+		r, err := render.NewTemplateWithConfig(w, cfg, opts.Template)
+		if err != nil {
+			return nil, err
+		}
+		r.Extras = opts.Extras
+		return r, nil
+
+	case TemplateNoColorStrat:
+		r, err := render.NewMonochromaticTemplateWithConfig(w, cfg, opts.Template)
+		if err != nil {
+			return nil, err
+		}
+		r.Extras = opts.Extras
+		return r, nil
 
 	default:
 		return nil, BadStratError{strat}
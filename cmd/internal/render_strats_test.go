@@ -17,15 +17,30 @@ func TestNewRenderer(t *testing.T) {
 	}{
 		{"console", render.ColoredConsole{}, nil},
 		{"no-color", render.MonochromaticConsole{}, nil},
+		{"json", render.JSON{}, nil},
+		{"json-compact", render.JSON{}, nil},
+		{"dot", render.Dot{}, nil},
+		{"mermaid", render.Mermaid{}, nil},
+		{"html", render.HTML{}, nil},
+		{"svg", render.SVG{}, nil},
+		{"map", render.Map{}, nil},
+		{"template", render.Template{}, nil},
+		{"template-no-color", render.Template{}, nil},
 		{"unknown", nil, internal.BadStratError{"unknown"}},
 	}
 
 	for _, tt := range cases {
 		t.Run(tt.stratName, func(t *testing.T) {
-			got, gotErr := internal.NewRenderer(tt.stratName)
+			got, gotErr := internal.NewRenderer(tt.stratName, internal.RendererOptions{})
 
 			assert.IsType(t, tt.want, got)
 			assert.Equal(t, tt.wantErr, gotErr)
 		})
 	}
 }
+
+func TestNewRendererBadTemplate(t *testing.T) {
+	_, err := internal.NewRenderer("template", internal.RendererOptions{Template: "{{.Nope}}"})
+
+	assert.Error(t, err)
+}
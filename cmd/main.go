@@ -2,12 +2,22 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"log"
 	"os"
 )
 
 func Main() {
 	err := root.ParseAndRun(context.Background(), os.Args[1:])
+
+	var exitErr exitError
+	if errors.As(err, &exitErr) {
+		if exitErr.Err != nil {
+			log.Print(exitErr.Err)
+		}
+		os.Exit(exitErr.Code)
+	}
+
 	if err != nil {
 		log.Fatal(err)
 	}
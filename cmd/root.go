@@ -2,16 +2,20 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
-	"os/exec"
-	"runtime"
-	"time"
 
 	"github.com/njhoffman/i3-tree/cmd/internal"
+	"github.com/njhoffman/i3-tree/pkg/config"
+	"github.com/njhoffman/i3-tree/pkg/fetch"
 	"github.com/njhoffman/i3-tree/pkg/i3treeviewer"
+	"github.com/njhoffman/i3-tree/pkg/prune"
+	"github.com/njhoffman/i3-tree/pkg/watch"
 	"github.com/peterbourgon/ff/v3/ffcli"
+	"go.i3wm.org/i3/v4"
 )
 
 var flagHelp = `i3-tree generates a user friendly view of the i3 tree
@@ -23,28 +27,130 @@ i3-tree
 # display all non empty workspaces
 i3-tree all
 
+# display the windows sent to the scratchpad, hidden or shown
+i3-tree scratch
+
+# display all non empty workspaces, including i3's internal one holding the scratchpad
+i3-tree --include-scratch all
+
+# display the workspace shown on every output
+i3-tree visible
+
 # show a specific workspace (for example, workspace 6)
 i3-tree 6
 
+# select workspaces by number (3 matches 3:mail), range, list, glob or regular expression
+# icon prefixed names have no number, e.g. '*4' selects "<icon> 4"
+i3-tree 1-4
+i3-tree 1,3,7
+i3-tree '*mail*'
+i3-tree '/^\d+:/'
+
+# only what is on an output: by name, glob, the focused output or the primary one
+i3-tree output:DP-2
+i3-tree output:HDMI-*
+i3-tree output:focused
+i3-tree output:primary
+
+# only windows matching a filter, with the containers they are in
+# fields: class, instance, title, mark, type, layout, output, workspace
+#         urgent, focused, fullscreen, floating
+# operators: = != ~ !~ (regular expressions, /.../i to ignore case), not, and, or, ( )
+# an argument without an operator, not or ( is a workspace name, e.g. i3-tree urgent
+# containers only match comparing their type, e.g. 'type=workspace and workspace=1'
+i3-tree 'class=Firefox or title~/jira/i and not floating'
+
+# search windows like grep -C: matches are highlighted, shown with their containers
+# and up to 1 window on each side
+i3-tree --match='class=Slack' --context=1
+
+# chain prune stages with +, each one pruning what the previous kept
+# any prune argument is a stage, as well as +focused, +simplify and +depth:N (levels below workspaces)
+# urgent, fullscreen and floating alone are filters as stages, e.g. +floating
+i3-tree ws:3 +floating +depth:2
+
+# only render 1 level below workspaces, as +depth:1 keeps, summarizing deeper containers
+# e.g. "… 7 windows: Firefox×3, Alacritty×4"
+i3-tree --depth=1 all
+
+# hide the split containers i3 nests windows in when they hold a single child
+i3-tree --simplify all
+
 # show focused workspace, with no colors
 i3-tree --render=no-color
 
+# output all non empty workspaces as JSON, e.g. for jq
+i3-tree --render=json all | jq '.children[].name'
+
+# draw all non empty workspaces with graphviz
+i3-tree --render=dot all | dot -Tsvg > tree.svg
+
+# standalone html page with collapsible containers
+i3-tree --render=html all > tree.html
+
+# svg image of the console output
+i3-tree --render=svg all > tree.svg
+
+# boxes showing where every window is on screen
+i3-tree --render=map all
+
+# one line per node in your own format (see TemplateNode in pkg/render)
+i3-tree --render=template --template='{{.Indent}}{{.Type}} {{.Class}}' all
+i3-tree --render=template --template='{{if .Class}}{{.ID}} {{format "window_class" .Class}}{{end}}' all
+i3-tree --render=template --template-file ~/.config/i3-tree/line.tmpl all
+
+# mermaid diagram of the focused workspace, to paste in a markdown mermaid block
+i3-tree --render=mermaid
+
+# talk to sway (used by default when $SWAYSOCK is set)
+i3-tree --from=sway
+
 # use mock data (useful if you don't have i3 running)
 i3-tree --from=mock
 
-# watch mode: refresh every 5 seconds (using default interval)
+# render a tree dumped with i3-msg -t get_tree
+i3-tree --from=file:/path/to/tree.json all
+i3-msg -t get_tree | i3-tree --from=- all
+
+# list what changed between two trees (see i3-tree diff --help)
+i3-tree diff before.json after.json
+
+# browse all non empty workspaces, focusing, moving or killing the selected window (see i3-tree tui --help)
+i3-tree tui all
+
+# act on the windows matching a filter expression (see i3-tree focus --help)
+i3-tree focus 'class=Slack'
+i3-tree kill --dry-run 'title~/Zoom/'
+i3-tree move --to ws:9 'class=mpv'
+
+# watch mode: redraw whenever i3 reports a change
+# falls back to refreshing every 5 seconds (using default interval)
 i3-tree --watch=0
 
-# watch mode: refresh every 2 seconds
+# watch mode, listing what changed under the tree (windows created, closed, moved, resized...)
+# and appending it to default_log_path of the config, or to another file
+i3-tree -W
+i3-tree -W /tmp/i3-tree.log
+i3-tree --watch-log=$HOME/i3-tree.log all
+
+# watch mode: falls back to refreshing every 2 seconds
 i3-tree -w 2
 
-# watch mode: refresh every 10 seconds
+# watch mode: falls back to refreshing every 10 seconds
 i3-tree --watch 10
 `
 
-var fetchStratName *string
-var renderStratName *string
+var fetchStratName = new(string)
+var renderStratName = new(string)
 var watchInterval *int
+var watchLog internal.OptionalString
+var templateText *string
+var templateFile *string
+var includeScratch = new(bool)
+var depth = new(int)
+var simplify = new(bool)
+var matchExpr = new(string)
+var matchContext = new(int)
 
 var rootFs *flag.FlagSet
 var root *ffcli.Command
@@ -52,25 +158,41 @@ var root *ffcli.Command
 func init() {
 	rootFs = flag.NewFlagSet("root", flag.ExitOnError)
 
-	fetchStratName = rootFs.String(
-		"from",
-		string(internal.FromI3),
-		"where to fetch the tree from. available: "+fmt.Sprintf("%s", internal.AvailableFetchStrats),
-	)
+	treeFlags(rootFs)
 
-	renderStratName = rootFs.String(
+	rootFs.StringVar(
+		renderStratName,
 		"render",
 		string(internal.ConsoleStrat), // Default
 		"where/how to render the output to. available: "+fmt.Sprintf("%s", internal.AvailableRendererStrats),
 	)
 
+	templateText = rootFs.String(
+		"template",
+		"",
+		"text/template executed for every node with --render=template or template-no-color (default: console like lines)",
+	)
+
+	templateFile = rootFs.String(
+		"template-file",
+		"",
+		"file to read the --render=template or template-no-color template from",
+	)
+
 	watchInterval = rootFs.Int(
 		"watch",
 		-1,
-		"watch mode: refresh every N seconds (default: 5 if flag is used without value)",
+		"watch mode: redraw on i3 events, or refresh every N seconds if they are unavailable (default: 5 if flag is used without value)",
 	)
 	rootFs.IntVar(watchInterval, "w", -1, "shorthand for --watch")
 
+	rootFs.Var(
+		&watchLog,
+		"watch-log",
+		"watch mode, listing what changed under the tree and appending it to a file: --watch-log=path or -W /path (default: default_log_path of the config)",
+	)
+	rootFs.Var(&watchLog, "W", "shorthand for --watch-log")
+
 	root = &ffcli.Command{
 		Name:       "i3-tree",
 		ShortUsage: "i3-tree",
@@ -78,34 +200,98 @@ func init() {
 		ShortHelp:  "Print the i3 tree in a user friendly format",
 		FlagSet:    rootFs,
 		Exec:       rootExec,
+		Subcommands: []*ffcli.Command{
+			newDiffCommand(),
+			newTUICommand(),
+			newFocusCommand(),
+			newKillCommand(),
+			newMoveCommand(),
+		},
 	}
 }
 
+// treeFlags defines the flags choosing where the tree comes from and what is kept of it
+// the root command and tui both define them, so they can be given before or after tui
+func treeFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		fetchStratName,
+		"from",
+		string(internal.Auto),
+		"where to fetch the tree from. available: "+fmt.Sprintf("%s", internal.AvailableFetchStrats),
+	)
+
+	fs.IntVar(
+		depth,
+		"depth",
+		0,
+		"console, no-color and svg: levels rendered below workspaces, as with +depth:N, deeper containers are summarized on one line, "+
+			"tui: containers this deep start collapsed (0: no limit)",
+	)
+
+	fs.StringVar(
+		matchExpr,
+		"match",
+		"",
+		"only keep the nodes matching a filter expression, highlighted, with their containers (searches all by default)",
+	)
+
+	fs.IntVar(
+		matchContext,
+		"context",
+		0,
+		"with --match, also keep up to N siblings on each side of the matching nodes, not of their containers",
+	)
+
+	fs.BoolVar(
+		simplify,
+		"simplify",
+		false,
+		"collapse split containers holding a single child, and the ones only splitting a workspace",
+	)
+
+	fs.BoolVar(
+		includeScratch,
+		"include-scratch",
+		false,
+		"show i3's internal __i3 output, holding the scratchpad, with all",
+	)
+}
+
 func rootExec(ctx context.Context, args []string) error {
+	args = watchLog.TakePath(args)
+
 	fetcher, err := internal.NewFetcher(*fetchStratName)
 	if err != nil {
 		return err
 	}
 
-	pruneArg := ""
-	if len(args) > 0 {
-		pruneArg = args[0]
-	}
-	pruner, err := internal.NewPruner(pruneArg)
+	extras := fetch.Extras{}
+	fetcher = internal.WithExtras(fetcher, extras)
+
+	match, err := matchPruner()
 	if err != nil {
 		return err
 	}
 
-	renderer, err := internal.NewRenderer(*renderStratName)
+	pruner, err := internal.NewPrunerChain(args, internal.PrunerOptions{
+		Fetcher:        fetcher,
+		IncludeScratch: *includeScratch,
+		Extras:         extras,
+		Simplify:       *simplify,
+		Match:          match,
+	})
 	if err != nil {
 		return err
 	}
 
-	i3tv := i3treeviewer.NewI3TreeViewer(
-		fetcher,
-		pruner,
-		renderer,
-	)
+	renderOpts, err := rendererOptions()
+	if err != nil {
+		return err
+	}
+	if match != nil {
+		renderOpts.Match = match
+	}
+	renderOpts.Extras = extras
 
 	// Determine watch interval
 	interval := *watchInterval
@@ -113,40 +299,106 @@ func rootExec(ctx context.Context, args []string) error {
 	// -1 means flag was not set (no watch mode)
 	// 0 means flag was set with value 0, which we treat as default (5 seconds)
 	// Any positive value is used as-is
-	if interval == -1 {
+	if interval == -1 && !watchLog.Given {
 		// No watch mode
+		renderer, err := internal.NewRenderer(*renderStratName, renderOpts)
+		if err != nil {
+			return err
+		}
+
+		i3tv := i3treeviewer.NewI3TreeViewer(fetcher, pruner, renderer)
 		return i3tv.View()
 	}
 
-	if interval == 0 {
+	if interval <= 0 {
 		interval = 5 // default interval when --watch is used with 0
 	}
 
-	// Watch mode: loop forever
-	for {
-		// Clear screen
-		clearScreen()
+	return watchExec(ctx, fetcher, pruner, renderOpts, interval)
+}
 
-		// Render tree
-		if err := i3tv.View(); err != nil {
-			return err
+// openWatchLog opens the file --watch-log appends to
+// its path, or the default_log_path of the config
+func openWatchLog() (*os.File, error) {
+	path := watchLog.Value
+	if path == "" {
+		cfg, err := config.Load()
+		if err != nil {
+			cfg = config.DefaultConfig()
 		}
+		path = cfg.DefaultLogPath
+	}
+
+	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+}
 
-		// Wait for interval
-		time.Sleep(time.Duration(interval) * time.Second)
+// loggingFetcher records what changed in every tree it fetches
+type loggingFetcher struct {
+	i3treeviewer.Fetcher
+	log *watch.Log
+}
+
+func (f loggingFetcher) Fetch() (i3.Tree, error) {
+	tree, err := f.Fetcher.Fetch()
+	if err != nil {
+		return tree, err
+	}
+
+	return tree, f.log.Record(&tree)
+}
+
+// matchPruner parses --match and --context
+// it returns nil when there's nothing to match
+func matchPruner() (*prune.Match, error) {
+	if *matchContext < 0 {
+		return nil, errors.New("--context can't be negative")
+	}
+
+	if *matchExpr == "" {
+		if *matchContext > 0 {
+			return nil, errors.New("--context needs --match")
+		}
+		return nil, nil
 	}
+
+	return prune.NewMatch(*matchExpr, *matchContext)
 }
 
-// clearScreen clears the terminal screen
-func clearScreen() {
-	var cmd *exec.Cmd
+// rendererOptions gathers the flags of the strategies that need more than their name
+func rendererOptions() (internal.RendererOptions, error) {
+	opts := internal.RendererOptions{
+		Template: *templateText,
+		Depth:    *depth,
+	}
+
+	if opts.Depth < 0 {
+		return opts, errors.New("--depth can't be negative")
+	}
+
+	if *templateFile != "" {
+		if opts.Template != "" {
+			return opts, errors.New("--template and --template-file can't be used together")
+		}
 
-	if runtime.GOOS == "windows" {
-		cmd = exec.Command("cmd", "/c", "cls")
-	} else {
-		cmd = exec.Command("clear")
+		data, err := ioutil.ReadFile(*templateFile)
+		if err != nil {
+			return opts, err
+		}
+		opts.Template = string(data)
 	}
 
-	cmd.Stdout = os.Stdout
-	cmd.Run()
+	return opts, nil
+}
+
+// eventSource subscribes to the window manager the tree is fetched from
+// it returns nil when the tree doesn't come from a window manager
+func eventSource(fetcher i3treeviewer.Fetcher) watch.EventSource {
+	switch f := fetcher.(type) {
+	case fetch.FromI3:
+		return watch.SubscribeI3()
+	case fetch.FromSway:
+		return watch.SubscribeSway(f.Socket())
+	default:
+		return nil
+	}
 }
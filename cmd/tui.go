@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/njhoffman/i3-tree/cmd/internal"
+	"github.com/njhoffman/i3-tree/pkg/command"
+	"github.com/njhoffman/i3-tree/pkg/config"
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/njhoffman/i3-tree/pkg/tui"
+	"github.com/peterbourgon/ff/v3/ffcli"
+)
+
+var tuiHelp = `tui browses the tree and acts on the selected node
+it takes the same prune arguments as i3-tree, e.g. all or ws:3
+
+KEYS
+j/k, arrows        move
+g/G, home/end      first/last node
+pgup/pgdown        page up/down (also Ctrl-U/Ctrl-D)
+h/l, left/right    collapse/expand a container, or go to its parent/first child
+space, tab         collapse or expand
+/                  search names, classes, instances and marks (enter keeps, esc cancels)
+n/N                next/previous match
+enter, f           focus
+x                  kill, once confirmed with y
+w                  move to a workspace
+t                  toggle floating
+m                  add a mark
+L                  change the layout of the container holding the node
+r                  reload the tree
+q, Ctrl-C          quit
+
+commands are sent as [con_id=...] criteria, like i3-msg does
+with --from=mock or a file, they are only shown
+
+EXAMPLES
+# every non empty workspace
+i3-tree tui all
+
+# the focused workspace, without the split containers holding a single window
+i3-tree tui --simplify
+
+# the windows matching a filter, with their containers, in every workspace
+i3-tree tui --match='class=Slack'
+
+# the containers splitting workspaces collapsed, to expand one by one
+i3-tree tui --depth=1 all
+
+# flags can also be given before tui
+i3-tree --from=mock tui all
+`
+
+// Render strategies of the tui command
+var tuiRenderStrats = []string{"console", "no-color"}
+
+func newTUICommand() *ffcli.Command {
+	fs := flag.NewFlagSet("tui", flag.ExitOnError)
+	treeFlags(fs)
+	fs.StringVar(
+		renderStratName,
+		"render",
+		"console",
+		"how to render the tree. available: "+fmt.Sprintf("%s", tuiRenderStrats),
+	)
+
+	return &ffcli.Command{
+		Name:       "tui",
+		ShortUsage: "i3-tree tui [flags] [prune args]",
+		ShortHelp:  "Browse the tree and act on its nodes",
+		LongHelp:   tuiHelp,
+		FlagSet:    fs,
+		Exec:       tuiExec,
+	}
+}
+
+func tuiExec(ctx context.Context, args []string) error {
+	render := *renderStratName
+	switch render {
+	case "console", "no-color":
+	default:
+		return internal.BadStratError{StratName: render}
+	}
+	if *depth < 0 {
+		return errors.New("--depth can't be negative")
+	}
+
+	fetcher, err := internal.NewFetcher(*fetchStratName)
+	if err != nil {
+		return err
+	}
+	if f, ok := fetcher.(fetch.FromFile); ok && f.Path == fetch.Stdin {
+		return errors.New("the tui reads keys from stdin, the tree can't be read from it too")
+	}
+
+	match, err := matchPruner()
+	if err != nil {
+		return err
+	}
+
+	pruner, err := internal.NewPrunerChain(args, internal.PrunerOptions{
+		Fetcher:        fetcher,
+		IncludeScratch: *includeScratch,
+		Simplify:       *simplify,
+		Match:          match,
+	})
+	if err != nil {
+		return err
+	}
+
+	dispatcher, err := internal.NewDispatcher(fetcher)
+	if errors.Is(err, internal.ErrNoWindowManager) {
+		// nothing to run the commands on, they are shown instead
+		dispatcher = &command.Recorder{}
+	} else if err != nil {
+		return err
+	}
+
+	m := tui.NewModel(fetcher, pruner, dispatcher, aurora.NewAurora(render == "console"))
+	m.Depth = *depth
+	if cfg, err := config.Load(); err == nil {
+		m.Config = cfg
+	}
+
+	return tui.Run(ctx, m, os.Stdin, os.Stdout)
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/njhoffman/i3-tree/cmd/internal"
+	"github.com/njhoffman/i3-tree/pkg/i3treeviewer"
+	"github.com/njhoffman/i3-tree/pkg/term"
+	"github.com/njhoffman/i3-tree/pkg/watch"
+)
+
+// watchExec redraws the tree in place on the alternate screen until interrupted
+// the terminal is restored on the way out, whatever the reason
+func watchExec(
+	ctx context.Context,
+	fetcher i3treeviewer.Fetcher,
+	pruner i3treeviewer.Pruner,
+	renderOpts internal.RendererOptions,
+	interval int,
+) error {
+	ctx, stop := term.NotifyStop(ctx)
+	defer stop()
+
+	// frames are rendered here, then drawn on the screen
+	var frame bytes.Buffer
+	renderOpts.Writer = &frame
+
+	renderer, err := internal.NewRenderer(*renderStratName, renderOpts)
+	if err != nil {
+		return err
+	}
+
+	var changes *watch.Log
+	viewFetcher := fetcher
+	if watchLog.Given {
+		f, err := openWatchLog()
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		changes = watch.NewLog(f)
+		viewFetcher = loggingFetcher{fetcher, changes}
+	}
+
+	i3tv := i3treeviewer.NewI3TreeViewer(viewFetcher, pruner, renderer)
+	au := aurora.NewAurora(*renderStratName != string(internal.ConsoleNoColorStrat))
+
+	screen := term.NewScreen(os.Stdout, term.Height(os.Stdout))
+	if err := screen.Enter(); err != nil {
+		return err
+	}
+
+	// refreshes come from the watcher and from resizes
+	var mu sync.Mutex
+	defer func() {
+		mu.Lock()
+		defer mu.Unlock()
+		screen.Exit()
+	}()
+
+	draw := func() error {
+		frame.Reset()
+		if err := i3tv.View(); err != nil {
+			return err
+		}
+
+		if changes != nil {
+			fmt.Fprintln(&frame)
+			room := screen.Height - strings.Count(frame.String(), "\n")
+			changes.Feed(&frame, au, room)
+		}
+
+		return screen.Draw(frame.String())
+	}
+
+	refresh := func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		return draw()
+	}
+
+	resized := make(chan os.Signal, 1)
+	term.NotifyResize(resized)
+	defer signal.Stop(resized)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-resized:
+			}
+
+			mu.Lock()
+			if ctx.Err() == nil {
+				screen.Resize(term.Height(os.Stdout))
+				draw()
+			}
+			mu.Unlock()
+		}
+	}()
+
+	err = runWatcher(ctx, watch.NewWatcher(refresh), fetcher, interval, func() {
+		// the notice was written over the frame, which is still the latest one
+		mu.Lock()
+		defer mu.Unlock()
+		screen.Clear()
+		screen.Draw(frame.String())
+	})
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// runWatcher refreshes on the window manager's events when it can,
+// and every interval seconds otherwise, calling fallback when it switches
+// after falling back, the tree drawn last is kept until the first interval is over
+func runWatcher(
+	ctx context.Context,
+	w *watch.Watcher,
+	fetcher i3treeviewer.Fetcher,
+	interval int,
+	fallback func(),
+) error {
+	// Only a running window manager can tell us when its tree changes
+	if src := eventSource(fetcher); src != nil {
+		err := w.Run(ctx, src)
+
+		var srcErr watch.SourceError
+		if !errors.As(err, &srcErr) {
+			return err
+		}
+		log.Printf("%s, refreshing every %d seconds instead", srcErr, interval)
+		fallback()
+		return w.PollAfter(ctx, time.Duration(interval)*time.Second)
+	}
+
+	return w.Poll(ctx, time.Duration(interval)*time.Second)
+}
@@ -0,0 +1,111 @@
+// Package command sends RUN_COMMAND messages to the window manager
+// and builds the commands acting on a single node, by con_id
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/njhoffman/i3-tree/pkg/ipc"
+	"go.i3wm.org/i3/v4"
+)
+
+// Dispatcher runs commands the way i3-msg does,
+// returning one result per command of a ; or , separated list
+type Dispatcher interface {
+	Run(command string) ([]i3.CommandResult, error)
+}
+
+// I3 runs commands on i3
+type I3 struct{}
+
+func (I3) Run(command string) ([]i3.CommandResult, error) {
+	results, err := i3.RunCommand(command)
+	// go-i3 fails on the first unsuccessful result, which the results tell about already
+	if len(results) > 0 {
+		return results, nil
+	}
+	return results, err
+}
+
+// Sway runs commands on the sway listening on SocketPath
+type Sway struct {
+	SocketPath string
+}
+
+func (s Sway) Run(command string) ([]i3.CommandResult, error) {
+	conn, err := ipc.Dial(s.SocketPath)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	reply, err := conn.Request(ipc.RunCommand, []byte(command))
+	if err != nil {
+		return nil, err
+	}
+
+	var results []i3.CommandResult
+	if err := json.Unmarshal(reply, &results); err != nil {
+		return nil, fmt.Errorf("invalid RUN_COMMAND reply %q: %w", reply, err)
+	}
+
+	return results, nil
+}
+
+// Failed returns the first unsuccessful result as an error, if any
+func Failed(command string, results []i3.CommandResult) error {
+	for _, r := range results {
+		if !r.Success {
+			return fmt.Errorf("%s: %s", command, r.Error)
+		}
+	}
+	return nil
+}
+
+// Criteria selects a single node, e.g. [con_id=94117230604000]
+func Criteria(id i3.NodeID) string {
+	return fmt.Sprintf("[con_id=%d]", id)
+}
+
+func Focus(id i3.NodeID) string {
+	return Criteria(id) + " focus"
+}
+
+func Kill(id i3.NodeID) string {
+	return Criteria(id) + " kill"
+}
+
+func MoveToWorkspace(id i3.NodeID, workspace string) string {
+	return Criteria(id) + " move container to workspace " + Quote(workspace)
+}
+
+func ToggleFloating(id i3.NodeID) string {
+	return Criteria(id) + " floating toggle"
+}
+
+// Mark adds a mark, keeping the ones the node has
+func Mark(id i3.NodeID, mark string) string {
+	return Criteria(id) + " mark --add " + Quote(mark)
+}
+
+// Layouts are the values Layout accepts
+var Layouts = []string{"splith", "splitv", "stacking", "tabbed", "toggle split"}
+
+// Layout sets the layout of the container holding the node, one of Layouts
+// anything else is rejected, as it would be run as a command of its own
+func Layout(id i3.NodeID, layout string) (string, error) {
+	for _, l := range Layouts {
+		if layout == l {
+			return Criteria(id) + " layout " + layout, nil
+		}
+	}
+	return "", fmt.Errorf("unknown layout %q, available: %s", layout, strings.Join(Layouts, ", "))
+}
+
+// Quote makes s a single argument of a command
+func Quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
@@ -0,0 +1,121 @@
+package command_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.i3wm.org/i3/v4"
+)
+
+func TestCommands(t *testing.T) {
+	id := i3.NodeID(94117230604000)
+
+	assert.Equal(t, "[con_id=94117230604000] focus", command.Focus(id))
+	assert.Equal(t, "[con_id=94117230604000] kill", command.Kill(id))
+	assert.Equal(t, `[con_id=94117230604000] move container to workspace "9: music"`, command.MoveToWorkspace(id, "9: music"))
+	assert.Equal(t, "[con_id=94117230604000] floating toggle", command.ToggleFloating(id))
+	assert.Equal(t, `[con_id=94117230604000] mark --add "todo"`, command.Mark(id, "todo"))
+}
+
+func TestLayout(t *testing.T) {
+	id := i3.NodeID(94117230604000)
+
+	got, err := command.Layout(id, "toggle split")
+	assert.NoError(t, err)
+	assert.Equal(t, "[con_id=94117230604000] layout toggle split", got)
+
+	for _, layout := range []string{"tabbed; kill", "tabbed, exec xterm", "stacked", ""} {
+		_, err := command.Layout(id, layout)
+		assert.EqualError(t, err, fmt.Sprintf(
+			"unknown layout %q, available: splith, splitv, stacking, tabbed, toggle split", layout,
+		))
+	}
+}
+
+func TestQuote(t *testing.T) {
+	assert.Equal(t, `"3"`, command.Quote("3"))
+	assert.Equal(t, `"say \"hi\""`, command.Quote(`say "hi"`))
+	assert.Equal(t, `"C:\\"`, command.Quote(`C:\`))
+}
+
+func TestFailed(t *testing.T) {
+	ok := []i3.CommandResult{{Success: true}, {Success: true}}
+	assert.NoError(t, command.Failed("focus; kill", ok))
+
+	failed := []i3.CommandResult{{Success: true}, {Success: false, Error: "No window matches"}}
+	assert.EqualError(t, command.Failed("focus; kill", failed), "focus; kill: No window matches")
+}
+
+func TestRecorder(t *testing.T) {
+	rec := &command.Recorder{}
+
+	results, err := rec.Run("[con_id=1] focus; [con_id=2] kill")
+	assert.NoError(t, err)
+	assert.Equal(t, []i3.CommandResult{{Success: true}, {Success: true}}, results)
+	assert.Equal(t, []string{"[con_id=1] focus; [con_id=2] kill"}, rec.Commands)
+
+	rec.Err = errors.New("no i3")
+	_, err = rec.Run("[con_id=1] kill")
+	assert.EqualError(t, err, "no i3")
+	assert.Len(t, rec.Commands, 2)
+}
+
+func TestSway(t *testing.T) {
+	path, got := fakeSway(t, `[{"success":true},{"success":false,"error":"No matching node"}]`)
+
+	results, err := command.Sway{SocketPath: path}.Run("[con_id=3] focus; [con_id=4] kill")
+	require.NoError(t, err)
+	assert.Equal(t, []i3.CommandResult{
+		{Success: true},
+		{Success: false, Error: "No matching node"},
+	}, results)
+	assert.Equal(t, "[con_id=3] focus; [con_id=4] kill", <-got)
+}
+
+func TestSwayNoSocket(t *testing.T) {
+	_, err := command.Sway{SocketPath: filepath.Join(t.TempDir(), "none.sock")}.Run("focus")
+	assert.Error(t, err)
+}
+
+// fakeSway answers a RUN_COMMAND with reply, sending the command it got to the returned channel
+func fakeSway(t *testing.T, reply string) (string, <-chan string) {
+	path := filepath.Join(t.TempDir(), "ipc.sock")
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	got := make(chan string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		header := make([]byte, 14)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		payload := make([]byte, binary.LittleEndian.Uint32(header[6:]))
+		if _, err := io.ReadFull(conn, payload); err != nil {
+			return
+		}
+		got <- string(payload)
+
+		msg := make([]byte, 14, 14+len(reply))
+		copy(msg, "i3-ipc")
+		binary.LittleEndian.PutUint32(msg[6:], uint32(len(reply)))
+		binary.LittleEndian.PutUint32(msg[10:], binary.LittleEndian.Uint32(header[10:]))
+		conn.Write(append(msg, reply...))
+	}()
+
+	return path, got
+}
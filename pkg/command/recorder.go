@@ -0,0 +1,33 @@
+package command
+
+import (
+	"strings"
+
+	"go.i3wm.org/i3/v4"
+)
+
+// Recorder is a Dispatcher keeping the commands it's given instead of running them
+// e.g. in tests, or to show what would be done
+type Recorder struct {
+	Commands []string
+	// Results are returned for every command, one success per command when nil
+	Results []i3.CommandResult
+	// Err is returned for every command, when set
+	Err error
+}
+
+func (r *Recorder) Run(command string) ([]i3.CommandResult, error) {
+	r.Commands = append(r.Commands, command)
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	if r.Results != nil {
+		return r.Results, nil
+	}
+
+	results := make([]i3.CommandResult, len(strings.Split(command, ";")))
+	for i := range results {
+		results[i].Success = true
+	}
+	return results, nil
+}
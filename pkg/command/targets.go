@@ -0,0 +1,57 @@
+package command
+
+import (
+	"github.com/njhoffman/i3-tree/pkg/prune"
+	"go.i3wm.org/i3/v4"
+)
+
+// Target is a window a command acts on
+type Target struct {
+	ID i3.NodeID
+	// Label names the window the way the console does, e.g. (firefox) Jira
+	Label string
+	// Workspace is the name of the workspace the window is on
+	Workspace string
+}
+
+// e.g. (firefox) Jira on workspace 3
+func (t Target) String() string {
+	switch t.Workspace {
+	case "":
+		return t.Label
+	case prune.ScratchWorkspace:
+		return t.Label + " on the scratchpad"
+	default:
+		return t.Label + " on workspace " + t.Workspace
+	}
+}
+
+// Targets returns the windows of tree matching filter, in the order of the tree
+// Containers are left out, so workspace=3 targets the windows on workspace 3
+func Targets(tree *i3.Tree, filter *prune.Filter) []Target {
+	if tree == nil || tree.Root == nil {
+		return nil
+	}
+	matching := filter.Matching(tree)
+
+	var targets []Target
+	var walk func(n *i3.Node, workspace string)
+	walk = func(n *i3.Node, workspace string) {
+		if n.Type == "workspace" {
+			workspace = n.Name
+		}
+		if matching[n] && prune.IsWindow(n) {
+			targets = append(targets, Target{ID: n.ID, Label: prune.WindowLabel(n), Workspace: workspace})
+		}
+
+		for _, c := range n.Nodes {
+			walk(c, workspace)
+		}
+		for _, c := range n.FloatingNodes {
+			walk(c, workspace)
+		}
+	}
+	walk(tree.Root, "")
+
+	return targets
+}
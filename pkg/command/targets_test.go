@@ -0,0 +1,57 @@
+package command_test
+
+import (
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/command"
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/njhoffman/i3-tree/pkg/prune"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.i3wm.org/i3/v4"
+)
+
+func TestTargets(t *testing.T) {
+	tree, err := fetch.FromFake{}.Fetch()
+	require.NoError(t, err)
+
+	cases := []struct {
+		expr string
+		want []command.Target
+	}{
+		{"title~/slack/i", []command.Target{{ID: 15, Label: "Slack", Workspace: "4"}}},
+		{"title~VLC", []command.Target{
+			{ID: 11, Label: "VLC media player", Workspace: "3"},
+			{ID: 14, Label: "VLC media player", Workspace: "4"},
+			{ID: 26, Label: "VLC media player", Workspace: "6"},
+		}},
+		// the containers matching are left out, the windows in them are kept
+		{"workspace=5 and title=/bin/bash", []command.Target{
+			{ID: 18, Label: "/bin/bash", Workspace: "5"},
+			{ID: 19, Label: "/bin/bash", Workspace: "5"},
+			{ID: 21, Label: "/bin/bash", Workspace: "5"},
+			{ID: 22, Label: "/bin/bash", Workspace: "5"},
+		}},
+		{"type=workspace", nil},
+		{"title=Zoom", nil},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.expr, func(t *testing.T) {
+			filter, err := prune.NewFilter(tt.expr)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, command.Targets(&tree, filter))
+		})
+	}
+}
+
+func TestTargetString(t *testing.T) {
+	assert.Equal(t, "(firefox) Jira on workspace 3", command.Target{
+		ID: 1, Label: "(firefox) Jira", Workspace: "3",
+	}.String())
+	assert.Equal(t, "(mpv) on the scratchpad", command.Target{
+		ID: 1, Label: "(mpv)", Workspace: prune.ScratchWorkspace,
+	}.String())
+	assert.Equal(t, "xterm", command.Target{ID: i3.NodeID(1), Label: "xterm"}.String())
+}
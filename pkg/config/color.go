@@ -206,3 +206,65 @@ func (nf NodeFormat) GetANSICode() string {
 	result += "m"
 	return result
 }
+
+// standardHex is the xterm palette for the 16 standard ANSI colors
+var standardHex = [16]string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+// ForegroundHex returns the foreground color as a #rrggbb string
+// or "" when the terminal default is used
+func (nf NodeFormat) ForegroundHex() string {
+	return ColorHex(nf.Foreground)
+}
+
+// BackgroundHex returns the background color as a #rrggbb string
+// or "" when the terminal default is used
+func (nf NodeFormat) BackgroundHex() string {
+	return ColorHex(nf.Background)
+}
+
+// ColorHex converts a configured color into a #rrggbb string
+// using the same mapping as ApplyFormat (xterm palette)
+// It returns "" for colors ApplyFormat leaves untouched
+func ColorHex(color int) string {
+	if color < 1 || color > 256 || color == 16 {
+		return ""
+	}
+
+	// 1-15 are the standard colors
+	if color < 16 {
+		return XtermHex(color)
+	}
+
+	// 17-256 are 256 color indexes offset by one
+	return XtermHex(color - 1)
+}
+
+// XtermHex converts an xterm 256 color palette index into a #rrggbb string
+func XtermHex(index int) string {
+	if index < 0 || index > 255 {
+		return ""
+	}
+
+	if index < 16 {
+		return standardHex[index]
+	}
+
+	// 6x6x6 color cube
+	if index < 232 {
+		index -= 16
+		level := func(v int) int {
+			if v == 0 {
+				return 0
+			}
+			return 55 + v*40
+		}
+		return fmt.Sprintf("#%02x%02x%02x", level(index/36), level(index/6%6), level(index%6))
+	}
+
+	// grayscale ramp
+	gray := 8 + (index-232)*10
+	return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray)
+}
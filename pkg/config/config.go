@@ -12,6 +12,9 @@ type Config struct {
 	// DefaultOutputType specifies the default output type: "raw", "all", or "focused"
 	DefaultOutputType string `json:"default_output_type"`
 
+	// DefaultLogPath is the file --watch-log appends changes to, when given no path
+	DefaultLogPath string `json:"default_log_path"`
+
 	// Display options
 	Display DisplayOptions `json:"display"`
 
@@ -53,6 +56,8 @@ type FormattingOptions struct {
 
 	// Window element formatting
 	WindowMarks NodeFormat `json:"window_marks"`
+	// e.g. {pid 4242, visible, scratchpad fresh}, only known for i3 and sway windows
+	WindowDetails NodeFormat `json:"window_details"`
 	WindowClass NodeFormat `json:"window_class"`
 	WindowTitle NodeFormat `json:"window_title"`
 
@@ -62,6 +67,9 @@ type FormattingOptions struct {
 	FocusBranches NodeFormat `json:"focus_branches"`
 	FocusClass    NodeFormat `json:"focus_class"`
 
+	// Nodes matching --match
+	Match NodeFormat `json:"match"`
+
 	// General elements
 	Brackets     NodeFormat `json:"brackets"`
 	TreeBranches NodeFormat `json:"tree_branches"`
@@ -104,6 +112,7 @@ type Attributes struct {
 func DefaultConfig() *Config {
 	return &Config{
 		DefaultOutputType: "focused",
+		DefaultLogPath:    "/tmp/i3-tree.log",
 		Display: DisplayOptions{
 			ShowWindowTitles: true,
 			ShowMarks:        true,
@@ -154,6 +163,11 @@ func DefaultConfig() *Config {
 				Background: 0,
 				Attributes: Attributes{},
 			},
+			WindowDetails: NodeFormat{
+				Foreground: 0,   // default
+				Background: 0,
+				Attributes: Attributes{Dim: true},
+			},
 			WindowClass: NodeFormat{
 				Foreground: 0,   // default
 				Background: 0,
@@ -185,6 +199,12 @@ func DefaultConfig() *Config {
 				Background: 0,
 				Attributes: Attributes{Bold: true},
 			},
+			// Nodes matching --match
+			Match: NodeFormat{
+				Foreground: 11,  // bright yellow
+				Background: 0,
+				Attributes: Attributes{Bold: true, Underline: true},
+			},
 			// General elements
 			Brackets: NodeFormat{
 				Foreground: 0,
@@ -275,7 +295,8 @@ func loadFromFile(path string) (*Config, error) {
 		return nil, err
 	}
 
-	config := &Config{}
+	// Start from the defaults, so entries added since the file was saved have a value
+	config := DefaultConfig()
 	if err := json.Unmarshal(data, config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
 	}
@@ -1,6 +1,7 @@
 package config_test
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
@@ -15,15 +16,16 @@ func TestDefaultConfig(t *testing.T) {
 
 	assert.NotNil(t, cfg)
 	assert.Equal(t, "focused", cfg.DefaultOutputType)
+	assert.Equal(t, "/tmp/i3-tree.log", cfg.DefaultLogPath)
 	assert.True(t, cfg.Display.ShowWindowTitles)
 	assert.True(t, cfg.Display.ShowMarks)
 	assert.True(t, cfg.Display.ShowWindowClass)
 	assert.True(t, cfg.Display.ShowIcons)
 
 	// Check some color defaults
-	assert.Equal(t, 6, cfg.Formatting.Workspace.Foreground) // cyan
-	assert.Equal(t, 4, cfg.Formatting.Con.Foreground)       // blue
-	assert.Equal(t, 1, cfg.Formatting.Marks.Foreground)     // red
+	assert.Equal(t, 6, cfg.Formatting.Workspace.Foreground)   // cyan
+	assert.Equal(t, 4, cfg.Formatting.Con.Foreground)         // blue
+	assert.Equal(t, 1, cfg.Formatting.WindowMarks.Foreground) // red
 
 	// Check icon defaults
 	assert.True(t, cfg.Icons.Fullscreen.Enabled)
@@ -102,3 +104,49 @@ func TestLoadNonExistentConfig(t *testing.T) {
 	_, err = os.Stat(defaultPath)
 	assert.NoError(t, err)
 }
+
+func TestLoadKeepsDefaultsOfMissingEntries(t *testing.T) {
+	originalHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", originalHome)
+
+	tmpDir := t.TempDir()
+	os.Setenv("HOME", tmpDir)
+
+	// a config saved before the match formatting existed
+	path := filepath.Join(tmpDir, ".config", "i3-tree.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	err := ioutil.WriteFile(path, []byte(`{"formatting": {"con": {"foreground": 2}}}`), 0644)
+	require.NoError(t, err)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 2, cfg.Formatting.Con.Foreground)
+	assert.Equal(t, config.DefaultConfig().Formatting.Match, cfg.Formatting.Match)
+}
+
+func TestColorHex(t *testing.T) {
+	cases := []struct {
+		color int
+		want  string
+	}{
+		{0, ""},
+		{1, "#cd0000"},
+		{6, "#00cdcd"},
+		{15, "#ffffff"},
+		{16, ""},
+		{17, "#000000"},
+		{81, "#5fd7d7"},
+		{197, "#ff0000"},
+		{233, "#080808"},
+		{256, "#eeeeee"},
+		{257, ""},
+	}
+
+	for _, tt := range cases {
+		assert.Equal(t, tt.want, config.ColorHex(tt.color), "color %d", tt.color)
+	}
+
+	nf := config.NodeFormat{Foreground: 4, Background: 255}
+	assert.Equal(t, "#0000ee", nf.ForegroundHex())
+	assert.Equal(t, "#e4e4e4", nf.BackgroundHex())
+}
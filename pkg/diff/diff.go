@@ -0,0 +1,348 @@
+// Package diff compares two i3 trees node by node
+package diff
+
+import (
+	"fmt"
+
+	"go.i3wm.org/i3/v4"
+)
+
+// Kind is the kind of difference a Change is about
+type Kind string
+
+const (
+	// the node is only in the new tree
+	Added Kind = "added"
+	// the node is only in the old tree
+	Removed Kind = "removed"
+	// the node has another parent, Old and New are NodeRefs
+	Moved Kind = "moved"
+	// the node is at another place among the siblings it had, Old and New are indexes
+	Reordered Kind = "reordered"
+	// the name, e.g. the window title, changed, Old and New are strings
+	Renamed Kind = "renamed"
+	// Old and New are layouts
+	LayoutChanged Kind = "layout"
+	// the position or the size changed, Old and New are i3.Rects
+	RectChanged Kind = "rect"
+	// one of focused, urgent, fullscreen or floating changed, Old and New are bools
+	FlagChanged Kind = "flag"
+)
+
+// NodeRef tells which node a change is about
+type NodeRef struct {
+	ID     i3.NodeID `json:"id"`
+	Window int64     `json:"window,omitempty"`
+	Type   string    `json:"type"`
+	Name   string    `json:"name"`
+	Class  string    `json:"class,omitempty"`
+}
+
+func newNodeRef(n *i3.Node) NodeRef {
+	return NodeRef{
+		ID:     n.ID,
+		Window: n.Window,
+		Type:   string(n.Type),
+		Name:   n.Name,
+		Class:  n.WindowProperties.Class,
+	}
+}
+
+// e.g. con 94117230604000 (firefox) "Jira"
+func (r NodeRef) String() string {
+	s := fmt.Sprintf("%s %d", r.Type, r.ID)
+	if r.Class != "" {
+		s += " (" + r.Class + ")"
+	}
+	if r.Name != "" {
+		s += fmt.Sprintf(" %q", r.Name)
+	}
+	return s
+}
+
+// Change is a difference between two trees about a single node
+type Change struct {
+	Kind Kind `json:"kind"`
+	// Node is the node as it is in the new tree, or in the old one when removed
+	Node NodeRef `json:"node"`
+	// Field is the flag that changed, for FlagChanged
+	Field string `json:"field,omitempty"`
+	// Old and New are the values before and after the change, see Kind
+	Old interface{} `json:"old,omitempty"`
+	New interface{} `json:"new,omitempty"`
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case Added, Removed:
+		return fmt.Sprintf("%s %s", c.Kind, c.Node)
+	case FlagChanged:
+		return fmt.Sprintf("%s %s %s: %v → %v", c.Kind, c.Node, c.Field, c.Old, c.New)
+	case Renamed:
+		return fmt.Sprintf("%s %s: %q → %q", c.Kind, c.Node, c.Old, c.New)
+	case RectChanged:
+		return fmt.Sprintf("%s %s: %s → %s", c.Kind, c.Node, rectString(c.Old), rectString(c.New))
+	default:
+		return fmt.Sprintf("%s %s: %v → %v", c.Kind, c.Node, c.Old, c.New)
+	}
+}
+
+// e.g. 960x1080+960+0
+func rectString(v interface{}) string {
+	r, ok := v.(i3.Rect)
+	if !ok {
+		return fmt.Sprint(v)
+	}
+	return fmt.Sprintf("%dx%d+%d+%d", r.Width, r.Height, r.X, r.Y)
+}
+
+// Trees lists the differences between the trees from and to
+// Nodes are matched by id, and by window id for the ones whose id isn't in the other tree,
+// e.g. after i3 restarted
+// Removals come first in the order of from, followed by the rest in the order of to
+func Trees(from *i3.Tree, to *i3.Tree) []Change {
+	before := index(from)
+	after := index(to)
+	pairs := match(before, after)
+
+	matched := make(map[i3.NodeID]bool, len(pairs))
+	for _, o := range pairs {
+		matched[o] = true
+	}
+
+	var changes []Change
+
+	for _, id := range before.order {
+		if !matched[id] {
+			changes = append(changes, Change{Kind: Removed, Node: newNodeRef(before.nodes[id].node)})
+		}
+	}
+
+	reordered := reorderings(before, after, pairs)
+
+	for _, id := range after.order {
+		a := after.nodes[id]
+		oldID, ok := pairs[id]
+		if !ok {
+			changes = append(changes, Change{Kind: Added, Node: newNodeRef(a.node)})
+			continue
+		}
+
+		b := before.nodes[oldID]
+		changes = append(changes, compare(b, a, pairs, reordered[id])...)
+	}
+
+	return changes
+}
+
+// compare lists what changed between the two versions of a node
+func compare(b indexed, a indexed, pairs map[i3.NodeID]i3.NodeID, isReordered bool) []Change {
+	ref := newNodeRef(a.node)
+	var changes []Change
+
+	add := func(kind Kind, field string, from interface{}, to interface{}) {
+		changes = append(changes, Change{Kind: kind, Node: ref, Field: field, Old: from, New: to})
+	}
+
+	if a.parent != nil && b.parent != nil {
+		if oldParent, ok := pairs[a.parent.ID]; !ok || oldParent != b.parent.ID {
+			add(Moved, "", newNodeRef(b.parent), newNodeRef(a.parent))
+		}
+	}
+	if isReordered {
+		add(Reordered, "", b.position, a.position)
+	}
+	if a.node.Name != b.node.Name {
+		add(Renamed, "", b.node.Name, a.node.Name)
+	}
+	if a.node.Layout != b.node.Layout {
+		add(LayoutChanged, "", string(b.node.Layout), string(a.node.Layout))
+	}
+	if a.node.Rect != b.node.Rect {
+		add(RectChanged, "", b.node.Rect, a.node.Rect)
+	}
+
+	flags := []struct {
+		name     string
+		old, new bool
+	}{
+		{"focused", b.node.Focused, a.node.Focused},
+		{"urgent", b.node.Urgent, a.node.Urgent},
+		{"fullscreen", b.node.FullscreenMode != 0, a.node.FullscreenMode != 0},
+		{"floating", b.floating, a.floating},
+	}
+	for _, f := range flags {
+		if f.old != f.new {
+			add(FlagChanged, f.name, f.old, f.new)
+		}
+	}
+
+	return changes
+}
+
+// indexed is a node with where it is in its tree
+type indexed struct {
+	node   *i3.Node
+	parent *i3.Node
+	// position among the children of parent, tiling ones first
+	position int
+	floating bool
+}
+
+type treeIndex struct {
+	nodes map[i3.NodeID]indexed
+	// ids in the order of the tree
+	order []i3.NodeID
+	// node ids by window id
+	windows map[int64]i3.NodeID
+}
+
+func index(tree *i3.Tree) treeIndex {
+	idx := treeIndex{
+		nodes:   make(map[i3.NodeID]indexed),
+		windows: make(map[int64]i3.NodeID),
+	}
+	if tree == nil || tree.Root == nil {
+		return idx
+	}
+
+	var walk func(n indexed)
+	walk = func(n indexed) {
+		if n.node.Type == "floating_con" {
+			n.floating = true
+		}
+
+		// a duplicate id keeps the first node, its children are still indexed
+		if _, seen := idx.nodes[n.node.ID]; !seen {
+			idx.nodes[n.node.ID] = n
+			idx.order = append(idx.order, n.node.ID)
+			if n.node.Window != 0 {
+				idx.windows[n.node.Window] = n.node.ID
+			}
+		}
+
+		for i, c := range children(n.node) {
+			walk(indexed{node: c, parent: n.node, position: i, floating: n.floating})
+		}
+	}
+	walk(indexed{node: tree.Root})
+
+	return idx
+}
+
+// children are the tiling children of a node, followed by the floating ones
+func children(n *i3.Node) []*i3.Node {
+	all := make([]*i3.Node, 0, len(n.Nodes)+len(n.FloatingNodes))
+	all = append(all, n.Nodes...)
+	return append(all, n.FloatingNodes...)
+}
+
+// match pairs the ids of after with the ids of the same nodes in before
+func match(before treeIndex, after treeIndex) map[i3.NodeID]i3.NodeID {
+	pairs := make(map[i3.NodeID]i3.NodeID)
+	used := make(map[i3.NodeID]bool)
+
+	for _, id := range after.order {
+		if _, ok := before.nodes[id]; ok {
+			pairs[id] = id
+			used[id] = true
+		}
+	}
+
+	// ids can change while windows stay, e.g. when i3 restarts
+	for _, id := range after.order {
+		if _, ok := pairs[id]; ok {
+			continue
+		}
+
+		w := after.nodes[id].node.Window
+		oldID, ok := before.windows[w]
+		if w == 0 || !ok || used[oldID] {
+			continue
+		}
+		pairs[id] = oldID
+		used[oldID] = true
+	}
+
+	return pairs
+}
+
+// reorderings finds the nodes whose place among the siblings they kept changed
+// The longest sequence of siblings in the same order is considered in place,
+// so moving one node is a single change
+func reorderings(before treeIndex, after treeIndex, pairs map[i3.NodeID]i3.NodeID) map[i3.NodeID]bool {
+	reordered := make(map[i3.NodeID]bool)
+
+	for _, id := range after.order {
+		oldID, ok := pairs[id]
+		if !ok {
+			continue
+		}
+		parentAfter := after.nodes[id].node
+		parentBefore := before.nodes[oldID].node
+
+		// the siblings in both, in the old order and in the new one
+		var olds, news []i3.NodeID
+		kept := make(map[i3.NodeID]bool)
+		for _, c := range children(parentAfter) {
+			if o, ok := pairs[c.ID]; ok && before.nodes[o].parent == parentBefore {
+				news = append(news, c.ID)
+				kept[o] = true
+			}
+		}
+		if len(news) < 2 {
+			continue
+		}
+		for _, c := range children(parentBefore) {
+			if kept[c.ID] {
+				olds = append(olds, c.ID)
+			}
+		}
+
+		inPlace := longestCommon(olds, news, pairs)
+		for _, n := range news {
+			if !inPlace[n] {
+				reordered[n] = true
+			}
+		}
+	}
+
+	return reordered
+}
+
+// longestCommon returns the ids of news in the longest common subsequence with olds
+func longestCommon(olds []i3.NodeID, news []i3.NodeID, pairs map[i3.NodeID]i3.NodeID) map[i3.NodeID]bool {
+	// lengths[i][j] is the length of the longest common subsequence of olds[i:] and news[j:]
+	lengths := make([][]int, len(olds)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(news)+1)
+	}
+	for i := len(olds) - 1; i >= 0; i-- {
+		for j := len(news) - 1; j >= 0; j-- {
+			switch {
+			case olds[i] == pairs[news[j]]:
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			case lengths[i+1][j] >= lengths[i][j+1]:
+				lengths[i][j] = lengths[i+1][j]
+			default:
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	common := make(map[i3.NodeID]bool)
+	for i, j := 0, 0; i < len(olds) && j < len(news); {
+		switch {
+		case olds[i] == pairs[news[j]]:
+			common[news[j]] = true
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	return common
+}
@@ -0,0 +1,144 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/diff"
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/stretchr/testify/assert"
+	"go.i3wm.org/i3/v4"
+)
+
+func changeStrings(changes []diff.Change) []string {
+	var s []string
+	for _, c := range changes {
+		s = append(s, c.String())
+	}
+	return s
+}
+
+func TestTreesSame(t *testing.T) {
+	from, to := fetch.FakeTree(), fetch.FakeTree()
+
+	assert.Empty(t, diff.Trees(from, to))
+	assert.Empty(t, diff.Trees(&i3.Tree{}, nil))
+}
+
+func TestTreesAddedAndRemoved(t *testing.T) {
+	from, to := fetch.FakeTree(), fetch.FakeTree()
+	ws4 := to.Root.Nodes[0].Nodes[3]
+	slack := ws4.Nodes[2]
+	ws4.Nodes[2] = &i3.Node{
+		ID:               100,
+		Name:             "htop",
+		Type:             "con",
+		Window:           7,
+		Rect:             slack.Rect,
+		WindowProperties: i3.WindowProperties{Class: "Alacritty"},
+	}
+
+	assert.Equal(t, []diff.Change{
+		{Kind: diff.Removed, Node: diff.NodeRef{ID: slack.ID, Type: "con", Name: "Slack"}},
+		{Kind: diff.Added, Node: diff.NodeRef{ID: 100, Window: 7, Type: "con", Name: "htop", Class: "Alacritty"}},
+	}, diff.Trees(from, to))
+	assert.Equal(t, `added con 100 (Alacritty) "htop"`, diff.Trees(from, to)[1].String())
+}
+
+func TestTreesMovedAndReordered(t *testing.T) {
+	from, to := fetch.FakeTree(), fetch.FakeTree()
+	hdmi0 := to.Root.Nodes[0]
+	ws1, ws2, ws3 := hdmi0.Nodes[0], hdmi0.Nodes[1], hdmi0.Nodes[2]
+
+	// move the VLC of workspace 3 to workspace 1
+	vlc := ws3.Nodes[1]
+	ws3.Nodes = ws3.Nodes[:1]
+	ws1.Nodes = append(ws1.Nodes, vlc)
+
+	// put the first window of workspace 2 last
+	ws2.Nodes = append(ws2.Nodes[1:], ws2.Nodes[0])
+
+	assert.Equal(t, []string{
+		`moved con 11 "VLC media player": workspace 9 "3" → workspace 3 "1"`,
+		`reordered con 6 "Twitter.com - Mozilla Firefox": 0 → 2`,
+	}, changeStrings(diff.Trees(from, to)))
+}
+
+func TestTreesSwapIsOneReordering(t *testing.T) {
+	from, to := fetch.FakeTree(), fetch.FakeTree()
+	ws3 := to.Root.Nodes[0].Nodes[2]
+	ws3.Nodes[0], ws3.Nodes[1] = ws3.Nodes[1], ws3.Nodes[0]
+
+	got := diff.Trees(from, to)
+
+	assert.Len(t, got, 1)
+	assert.Equal(t, diff.Reordered, got[0].Kind)
+}
+
+func TestTreesDuplicateID(t *testing.T) {
+	from, to := fetch.FakeTree(), fetch.FakeTree()
+	// the first container of workspace 5 has the id of workspace 1 in both trees
+	from.Root.Nodes[0].Nodes[4].Nodes[0].ID = 3
+	split := to.Root.Nodes[0].Nodes[4].Nodes[0]
+	split.ID = 3
+	split.Nodes = split.Nodes[1:]
+
+	assert.Equal(t, []string{`removed con 18 "/bin/bash"`}, changeStrings(diff.Trees(from, to)))
+}
+
+func TestTreesRenamedLayoutRectAndFlags(t *testing.T) {
+	from, to := fetch.FakeTree(), fetch.FakeTree()
+	ws2 := to.Root.Nodes[0].Nodes[1]
+	ws2.Layout = "tabbed"
+
+	chromium := ws2.Nodes[2]
+	chromium.Name = "github.com - Chromium"
+	chromium.Urgent = true
+	chromium.FullscreenMode = 1
+	chromium.Rect = i3.Rect{Width: 1920, Height: 1080}
+
+	assert.Equal(t, []string{
+		`layout workspace 5 "2": stacked → tabbed`,
+		`renamed con 8 "github.com - Chromium": "duckduckgo.com - Chromium" → "github.com - Chromium"`,
+		`rect con 8 "github.com - Chromium": 1920x1020+0+60 → 1920x1080+0+0`,
+		`flag con 8 "github.com - Chromium" urgent: false → true`,
+		`flag con 8 "github.com - Chromium" fullscreen: false → true`,
+	}, changeStrings(diff.Trees(from, to)))
+}
+
+func TestTreesFloating(t *testing.T) {
+	from, to := fetch.FakeTree(), fetch.FakeTree()
+	ws1 := to.Root.Nodes[0].Nodes[0]
+	firefox := ws1.Nodes[0]
+	ws1.Nodes = nil
+	ws1.FloatingNodes = []*i3.Node{{ID: 100, Type: "floating_con", Nodes: []*i3.Node{firefox}}}
+
+	assert.Equal(t, []string{
+		`added floating_con 100`,
+		`moved con 4 "Reddit.com - Mozilla Firefox": workspace 3 "1" → floating_con 100`,
+		`flag con 4 "Reddit.com - Mozilla Firefox" floating: false → true`,
+	}, changeStrings(diff.Trees(from, to)))
+}
+
+func TestTreesMatchByWindow(t *testing.T) {
+	from := &i3.Tree{Root: &i3.Node{ID: 1, Type: "root", Nodes: []*i3.Node{
+		{ID: 2, Name: "vim", Type: "con", Window: 42},
+		{ID: 3, Name: "htop", Type: "con", Window: 43},
+	}}}
+	// i3 restarted: the containers got new ids, the windows kept theirs
+	to := &i3.Tree{Root: &i3.Node{ID: 1, Type: "root", Nodes: []*i3.Node{
+		{ID: 12, Name: "vim", Type: "con", Window: 42},
+		{ID: 13, Name: "htop", Type: "con", Window: 43},
+		{ID: 14, Name: "top", Type: "con", Window: 44},
+	}}}
+
+	assert.Equal(t, []string{`added con 14 "top"`}, changeStrings(diff.Trees(from, to)))
+}
+
+func TestTreesFromDump(t *testing.T) {
+	from, err := fetch.FromFile{Path: "../fetch/testdata/get_tree.json"}.Fetch()
+	assert.NoError(t, err)
+	to, err := fetch.FromFile{Path: "../fetch/testdata/get_tree.json"}.Fetch()
+	assert.NoError(t, err)
+
+	assert.Empty(t, diff.Trees(&from, &to))
+}
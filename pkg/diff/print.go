@@ -0,0 +1,48 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/logrusorgru/aurora"
+)
+
+// Print writes one change per line, its kind emphasized
+func Print(w io.Writer, changes []Change, au aurora.Aurora) {
+	for _, c := range changes {
+		line := c.String()
+		kind := string(c.Kind)
+		fmt.Fprintln(w, au.Bold(kindColor(au, c.Kind, kind)).String()+line[len(kind):])
+	}
+}
+
+// PrintJSON writes the changes as a JSON array, see Change for the schema
+// An empty array means the trees are the same
+func PrintJSON(w io.Writer, changes []Change, indent bool) error {
+	if changes == nil {
+		changes = []Change{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if indent {
+		enc.SetIndent("", "  ")
+	}
+	return enc.Encode(changes)
+}
+
+func kindColor(au aurora.Aurora, k Kind, s string) aurora.Value {
+	switch k {
+	case Added:
+		return au.Green(s)
+	case Removed:
+		return au.Red(s)
+	case Moved, Reordered:
+		return au.Yellow(s)
+	case FlagChanged:
+		return au.Cyan(s)
+	default:
+		return au.Magenta(s)
+	}
+}
@@ -0,0 +1,63 @@
+package diff_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/njhoffman/i3-tree/pkg/diff"
+	"github.com/stretchr/testify/assert"
+	"go.i3wm.org/i3/v4"
+)
+
+var printed = []diff.Change{
+	{Kind: diff.Removed, Node: diff.NodeRef{ID: 2, Type: "con", Name: "Slack"}},
+	{
+		Kind: diff.Moved,
+		Node: diff.NodeRef{ID: 3, Type: "con", Name: "vim", Class: "Alacritty"},
+		Old:  diff.NodeRef{ID: 4, Type: "workspace", Name: "3"},
+		New:  diff.NodeRef{ID: 5, Type: "workspace", Name: "1"},
+	},
+	{
+		Kind: diff.RectChanged,
+		Node: diff.NodeRef{ID: 3, Type: "con", Name: "vim", Class: "Alacritty"},
+		Old:  i3.Rect{Width: 960, Height: 1080},
+		New:  i3.Rect{X: 960, Width: 960, Height: 1080},
+	},
+	{Kind: diff.FlagChanged, Node: diff.NodeRef{ID: 3, Type: "con"}, Field: "urgent", Old: false, New: true},
+}
+
+func TestPrint(t *testing.T) {
+	var buf bytes.Buffer
+	diff.Print(&buf, printed, aurora.NewAurora(false))
+
+	assert.Equal(t, `removed con 2 "Slack"
+moved con 3 (Alacritty) "vim": workspace 4 "3" → workspace 5 "1"
+rect con 3 (Alacritty) "vim": 960x1080+0+0 → 960x1080+960+0
+flag con 3 urgent: false → true
+`, buf.String())
+}
+
+func TestPrintColored(t *testing.T) {
+	var buf bytes.Buffer
+	diff.Print(&buf, printed[:1], aurora.NewAurora(true))
+
+	assert.Equal(t, "\x1b[1;31mremoved\x1b[0m con 2 \"Slack\"\n", buf.String())
+}
+
+func TestPrintJSON(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, diff.PrintJSON(&buf, printed[1:], false))
+
+	assert.Equal(t, `[`+
+		`{"kind":"moved","node":{"id":3,"type":"con","name":"vim","class":"Alacritty"},`+
+		`"old":{"id":4,"type":"workspace","name":"3"},"new":{"id":5,"type":"workspace","name":"1"}},`+
+		`{"kind":"rect","node":{"id":3,"type":"con","name":"vim","class":"Alacritty"},`+
+		`"old":{"x":0,"y":0,"width":960,"height":1080},"new":{"x":960,"y":0,"width":960,"height":1080}},`+
+		`{"kind":"flag","node":{"id":3,"type":"con","name":""},"field":"urgent","old":false,"new":true}`+
+		"]\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, diff.PrintJSON(&buf, nil, true))
+	assert.Equal(t, "[]\n", buf.String())
+}
@@ -0,0 +1,72 @@
+package fetch
+
+import "go.i3wm.org/i3/v4"
+
+// Extra is what a GET_TREE reply tells about a node that go-i3 doesn't decode
+type Extra struct {
+	// PID of the process owning a sway window, 0 when unknown
+	PID int
+	// Visible is only known for sway windows and workspaces, nil otherwise
+	Visible *bool
+	// InhibitIdle is true when a sway window keeps the outputs from idling
+	InhibitIdle bool
+	// ScratchpadState is fresh or changed for the containers sent to the scratchpad,
+	// hidden or shown, none for the others and empty when unknown
+	ScratchpadState string
+}
+
+// InScratchpad tells if the node was sent to the scratchpad
+func (e Extra) InScratchpad() bool {
+	return e.ScratchpadState != "" && e.ScratchpadState != "none"
+}
+
+// Extras are the Extra of the nodes of the last tree a fetcher fetched, by node id
+// Nodes the fetcher knows nothing more about are left out
+type Extras map[i3.NodeID]Extra
+
+// reset forgets the nodes of the previous tree
+func (e Extras) reset() {
+	for id := range e {
+		delete(e, id)
+	}
+}
+
+// i3Node is an i3.Node plus the fields go-i3 doesn't decode, kept as Extra
+type i3Node struct {
+	i3.Node
+
+	ScratchpadState string `json:"scratchpad_state"`
+
+	// shadow i3.Node's children so they are decoded as i3Node too
+	Nodes         []*i3Node `json:"nodes"`
+	FloatingNodes []*i3Node `json:"floating_nodes"`
+}
+
+// toI3 returns the i3.Node, keeping its other fields in extras unless it's nil
+func (n *i3Node) toI3(extras Extras) *i3.Node {
+	if n == nil {
+		return nil
+	}
+
+	node := n.Node
+	if extras != nil && n.ScratchpadState != "" {
+		extras[node.ID] = Extra{ScratchpadState: n.ScratchpadState}
+	}
+
+	node.Nodes = i3Nodes(n.Nodes, extras)
+	node.FloatingNodes = i3Nodes(n.FloatingNodes, extras)
+
+	return &node
+}
+
+func i3Nodes(nodes []*i3Node, extras Extras) []*i3.Node {
+	if nodes == nil {
+		return nil
+	}
+
+	res := make([]*i3.Node, 0, len(nodes))
+	for _, n := range nodes {
+		res = append(res, n.toI3(extras))
+	}
+	return res
+}
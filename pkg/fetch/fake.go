@@ -9,6 +9,32 @@ func (_ FromFake) Fetch() (i3.Tree, error) {
 	return fakeTree(), nil
 }
 
+// Outputs lists the outputs of the fake tree
+// the first one being the primary output
+func (_ FromFake) Outputs() ([]i3.Output, error) {
+	tree := fakeTree()
+
+	var outputs []i3.Output
+	for i, n := range tree.Root.Nodes {
+		outputs = append(outputs, i3.Output{
+			Name:             n.Name,
+			Active:           true,
+			Primary:          i == 0,
+			CurrentWorkspace: n.Nodes[0].Name,
+			Rect:             n.Rect,
+		})
+	}
+
+	return outputs, nil
+}
+
+// FakeTree returns the fake tree, a new one on every call, that can be changed
+// e.g. to compare it with another one
+func FakeTree() *i3.Tree {
+	tree := fakeTree()
+	return &tree
+}
+
 func fakeTree() i3.Tree {
 	// Horizontal Split
 	ws1 := &i3.Node{
@@ -180,7 +206,84 @@ func fakeTree() i3.Tree {
 		},
 	}
 
-	return i3.Tree{
+	tree := i3.Tree{
 		Root: &node,
 	}
+
+	// give every node an id and the geometry i3 would have given it
+	// with two 1920x1080 monitors side by side
+	id := i3.NodeID(0)
+	assignIDs(tree.Root, &id)
+	node.Rect = i3.Rect{Width: 3840, Height: 1080}
+	for i, output := range node.Nodes {
+		output.Rect = i3.Rect{X: int64(i) * 1920, Width: 1920, Height: 1080}
+		for _, ws := range output.Nodes {
+			assignRects(ws, output.Rect)
+		}
+	}
+
+	return tree
+}
+
+// height of the window titles of tabbed and stacked containers
+const fakeDecoHeight = 20
+
+func assignIDs(node *i3.Node, id *i3.NodeID) {
+	*id++
+	node.ID = *id
+
+	for _, n := range node.Nodes {
+		assignIDs(n, id)
+	}
+	for _, n := range node.FloatingNodes {
+		assignIDs(n, id)
+	}
+}
+
+// assignRects splits rect between node's children according to its layout
+// and places its floating children over it
+func assignRects(node *i3.Node, rect i3.Rect) {
+	node.Rect = rect
+
+	count := int64(len(node.Nodes))
+
+	for i, n := range node.Nodes {
+		i := int64(i)
+		child := rect
+
+		switch node.Layout {
+		case i3.Layout(i3.SplitH):
+			child.Width = rect.Width / count
+			child.X = rect.X + i*child.Width
+		case i3.Layout(i3.SplitV):
+			child.Height = rect.Height / count
+			child.Y = rect.Y + i*child.Height
+		case i3.Layout(i3.Tabbed):
+			// one row of tabs, each child owns a slice of it
+			child.Y += fakeDecoHeight
+			child.Height -= fakeDecoHeight
+			n.DecoRect = i3.Rect{X: i * rect.Width / count, Width: rect.Width / count, Height: fakeDecoHeight}
+		case i3.Layout(i3.Stacked):
+			// one row of titles per child
+			child.Y += count * fakeDecoHeight
+			child.Height -= count * fakeDecoHeight
+			n.DecoRect = i3.Rect{Y: i * fakeDecoHeight, Width: rect.Width, Height: fakeDecoHeight}
+		}
+
+		assignRects(n, child)
+	}
+
+	// floating containers keep their rect when given one, or are centered on rect
+	for _, n := range node.FloatingNodes {
+		floating := n.Rect
+		if floating == (i3.Rect{}) {
+			floating = i3.Rect{
+				X:      rect.X + rect.Width/4,
+				Y:      rect.Y + rect.Height/4,
+				Width:  rect.Width / 2,
+				Height: rect.Height / 2,
+			}
+		}
+		assignRects(n, floating)
+	}
 }
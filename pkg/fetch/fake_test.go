@@ -13,3 +13,17 @@ func TestFromFake(t *testing.T) {
 	assert.Nil(t, gotErr)
 	assert.NotNil(t, got)
 }
+
+func TestFromFakeOutputs(t *testing.T) {
+	f := fetch.FromFake{}
+	got, gotErr := f.Outputs()
+
+	assert.Nil(t, gotErr)
+	assert.Len(t, got, 2)
+	assert.Equal(t, "HDMI-0", got[0].Name)
+	assert.True(t, got[0].Primary)
+	assert.Equal(t, "1", got[0].CurrentWorkspace)
+	assert.Equal(t, "HDMI-1", got[1].Name)
+	assert.False(t, got[1].Primary)
+	assert.Equal(t, "6", got[1].CurrentWorkspace)
+}
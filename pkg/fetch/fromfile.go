@@ -0,0 +1,116 @@
+package fetch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"go.i3wm.org/i3/v4"
+)
+
+// Stdin is the path FromFile understands as "read from stdin"
+const Stdin = "-"
+
+// FromFile fetches a tree from a GET_TREE JSON dump
+// such as the one produced by `i3-msg -t get_tree`
+// The fields go-i3 doesn't decode are kept in Extras, when set
+type FromFile struct {
+	Path   string
+	Extras Extras
+}
+
+func (f FromFile) Fetch() (i3.Tree, error) {
+	var r io.Reader = os.Stdin
+	name := "<stdin>"
+
+	if f.Path != Stdin {
+		file, err := os.Open(f.Path)
+		if err != nil {
+			return i3.Tree{}, err
+		}
+		defer file.Close()
+		r = file
+		name = f.Path
+	}
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return i3.Tree{}, err
+	}
+
+	return ParseTree(name, data, f.Extras)
+}
+
+// ParseError points at the position in a tree dump that could not be parsed
+type ParseError struct {
+	Path   string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.Path, e.Line, e.Column, e.Err)
+}
+
+func (e ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseTree unmarshals a GET_TREE reply into an i3.Tree
+// and the fields go-i3 doesn't decode into extras, unless it's nil
+// path is only used to report errors
+func ParseTree(path string, data []byte, extras Extras) (i3.Tree, error) {
+	var root *i3Node
+
+	if err := json.Unmarshal(data, &root); err != nil {
+		return i3.Tree{}, newParseError(path, data, err)
+	}
+
+	if extras != nil {
+		extras.reset()
+	}
+
+	return i3.Tree{
+		Root: root.toI3(extras),
+	}, nil
+}
+
+func newParseError(path string, data []byte, err error) error {
+	var offset int64
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return err
+	}
+
+	// the offset is the number of bytes read when the error happened
+	// so the offending byte is the one right before it
+	if offset > 0 {
+		offset--
+	}
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := int(offset) - bytes.LastIndexByte(before, '\n')
+
+	return ParseError{
+		Path:   path,
+		Line:   line,
+		Column: column,
+		Err:    err,
+	}
+}
@@ -0,0 +1,87 @@
+package fetch_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.i3wm.org/i3/v4"
+)
+
+func TestFromFile(t *testing.T) {
+	f := fetch.FromFile{Path: filepath.Join("testdata", "get_tree.json")}
+	got, gotErr := f.Fetch()
+
+	require.Nil(t, gotErr)
+	require.NotNil(t, got.Root)
+	assert.Equal(t, i3.NodeType(i3.Root), got.Root.Type)
+	assert.Len(t, got.Root.Nodes, 2)
+
+	edp := got.Root.Nodes[1]
+	assert.Equal(t, "eDP-1", edp.Name)
+
+	ws1 := edp.Nodes[1].Nodes[0]
+	assert.Equal(t, "1", ws1.Name)
+	assert.Equal(t, "Alacritty", ws1.Nodes[0].WindowProperties.Class)
+	assert.True(t, ws1.Nodes[0].Focused)
+	assert.Equal(t, []string{"_last"}, ws1.Nodes[0].Marks)
+	assert.Equal(t, i3.Rect{X: 960, Y: 24, Width: 960, Height: 1056}, ws1.Nodes[1].Rect)
+}
+
+func TestFromFileExtras(t *testing.T) {
+	extras := fetch.Extras{}
+	f := fetch.FromFile{Path: filepath.Join("testdata", "get_tree.json"), Extras: extras}
+	_, gotErr := f.Fetch()
+	require.Nil(t, gotErr)
+
+	scratch := extras[94117230621440]
+	assert.Equal(t, "changed", scratch.ScratchpadState)
+	assert.True(t, scratch.InScratchpad())
+
+	window := extras[94117230622576]
+	assert.Equal(t, "none", window.ScratchpadState)
+	assert.False(t, window.InScratchpad())
+}
+
+func TestFromFileMissing(t *testing.T) {
+	f := fetch.FromFile{Path: filepath.Join("testdata", "missing.json")}
+	_, gotErr := f.Fetch()
+
+	assert.Error(t, gotErr)
+}
+
+func TestParseTree(t *testing.T) {
+	t.Run("null tree", func(t *testing.T) {
+		got, gotErr := fetch.ParseTree("null.json", []byte("null"), nil)
+
+		assert.Nil(t, gotErr)
+		assert.Equal(t, i3.Tree{}, got)
+	})
+
+	cases := []struct {
+		name   string
+		data   string
+		line   int
+		column int
+	}{
+		{"invalid character", "{\n  \"id\": 1,\n  \"name\": root\n}", 3, 11},
+		{"wrong type", "{\n  \"focused\": \"yes\"\n}", 2, 18},
+		{"truncated", "{\n  \"nodes\": [", 2, 12},
+		{"empty", "", 1, 1},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, gotErr := fetch.ParseTree("tree.json", []byte(tt.data), nil)
+
+			var parseErr fetch.ParseError
+			require.True(t, errors.As(gotErr, &parseErr), gotErr)
+			assert.Equal(t, "tree.json", parseErr.Path)
+			assert.Equal(t, tt.line, parseErr.Line)
+			assert.Equal(t, tt.column, parseErr.Column)
+		})
+	}
+}
@@ -1,9 +1,39 @@
 package fetch
 
-import "go.i3wm.org/i3/v4"
+import (
+	"github.com/njhoffman/i3-tree/pkg/ipc"
+	"go.i3wm.org/i3/v4"
+)
 
-type FromI3 struct{}
+// FromI3 fetches the tree from i3
+// The fields go-i3 doesn't decode are kept in Extras, when set
+type FromI3 struct {
+	Extras Extras
+}
 
+// Fetch asks i3 for the tree over its IPC socket, rather than with i3.GetTree
+// to get the raw reply the Extras are decoded from
 func (i FromI3) Fetch() (i3.Tree, error) {
-	return i3.GetTree()
+	path, err := i3.SocketPathHook()
+	if err != nil {
+		return i3.Tree{}, err
+	}
+
+	conn, err := ipc.Dial(path)
+	if err != nil {
+		return i3.Tree{}, err
+	}
+	defer conn.Close()
+
+	reply, err := conn.Request(ipc.GetTree, nil)
+	if err != nil {
+		return i3.Tree{}, err
+	}
+
+	return ParseTree("i3", reply, i.Extras)
+}
+
+// Outputs lists the outputs i3 knows, active or not
+func (i FromI3) Outputs() ([]i3.Output, error) {
+	return i3.GetOutputs()
 }
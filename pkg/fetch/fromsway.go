@@ -0,0 +1,145 @@
+package fetch
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/njhoffman/i3-tree/pkg/ipc"
+	"go.i3wm.org/i3/v4"
+)
+
+// SwaySockEnv is the environment variable sway exports its IPC socket path in
+const SwaySockEnv = "SWAYSOCK"
+
+// FromSway fetches the tree from sway's IPC socket
+// Which defaults to the one in $SWAYSOCK
+// The sway only fields of the nodes are kept in Extras, when set
+type FromSway struct {
+	SocketPath string
+	Extras     Extras
+}
+
+// Socket returns the path of the IPC socket to talk to
+func (s FromSway) Socket() string {
+	if s.SocketPath != "" {
+		return s.SocketPath
+	}
+	return os.Getenv(SwaySockEnv)
+}
+
+func (s FromSway) Fetch() (i3.Tree, error) {
+	conn, err := ipc.Dial(s.Socket())
+	if err != nil {
+		return i3.Tree{}, err
+	}
+	defer conn.Close()
+
+	reply, err := conn.Request(ipc.GetTree, nil)
+	if err != nil {
+		return i3.Tree{}, err
+	}
+
+	return ParseSwayTree("sway", reply, s.Extras)
+}
+
+// Outputs lists the outputs sway knows, active or not
+func (s FromSway) Outputs() ([]i3.Output, error) {
+	conn, err := ipc.Dial(s.Socket())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	reply, err := conn.Request(ipc.GetOutputs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var outputs []i3.Output
+	if err := json.Unmarshal(reply, &outputs); err != nil {
+		return nil, newParseError("sway", reply, err)
+	}
+
+	return outputs, nil
+}
+
+// swayNode is an i3.Node plus the sway only fields we can show
+// pid, visible, inhibit_idle and scratchpad_state have no i3 counterpart, they are kept as Extra
+type swayNode struct {
+	i3.Node
+
+	AppID       string `json:"app_id"`
+	Shell       string `json:"shell"`
+	PID         int    `json:"pid"`
+	Visible     *bool  `json:"visible"`
+	InhibitIdle bool   `json:"inhibit_idle"`
+
+	ScratchpadState string `json:"scratchpad_state"`
+
+	// shadow i3.Node's children so they are decoded as sway nodes too
+	Nodes         []*swayNode `json:"nodes"`
+	FloatingNodes []*swayNode `json:"floating_nodes"`
+}
+
+// ParseSwayTree unmarshals a sway GET_TREE reply into an i3.Tree
+// and the sway only fields of its nodes into extras, unless it's nil
+// path is only used to report errors
+func ParseSwayTree(path string, data []byte, extras Extras) (i3.Tree, error) {
+	var root *swayNode
+
+	if err := json.Unmarshal(data, &root); err != nil {
+		return i3.Tree{}, newParseError(path, data, err)
+	}
+
+	if extras != nil {
+		extras.reset()
+	}
+
+	return i3.Tree{
+		Root: root.toI3(extras),
+	}, nil
+}
+
+// toI3 maps a sway node into what the renderers understand
+func (s *swayNode) toI3(extras Extras) *i3.Node {
+	if s == nil {
+		return nil
+	}
+
+	n := s.Node
+
+	if extras != nil && (s.PID != 0 || s.Visible != nil || s.InhibitIdle || s.ScratchpadState != "") {
+		extras[n.ID] = Extra{
+			PID:             s.PID,
+			Visible:         s.Visible,
+			InhibitIdle:     s.InhibitIdle,
+			ScratchpadState: s.ScratchpadState,
+		}
+	}
+
+	// Wayland native windows have no X11 window properties,
+	// app_id is the closest thing to a class
+	// xwayland windows keep their X11 class
+	if s.Shell != "xwayland" && s.AppID != "" && n.WindowProperties.Class == "" {
+		n.WindowProperties.Class = s.AppID
+		n.WindowProperties.Instance = s.AppID
+		n.WindowProperties.Title = n.Name
+	}
+
+	n.Nodes = toI3Nodes(s.Nodes, extras)
+	n.FloatingNodes = toI3Nodes(s.FloatingNodes, extras)
+
+	return &n
+}
+
+func toI3Nodes(nodes []*swayNode, extras Extras) []*i3.Node {
+	if nodes == nil {
+		return nil
+	}
+
+	res := make([]*i3.Node, 0, len(nodes))
+	for _, n := range nodes {
+		res = append(res, n.toI3(extras))
+	}
+	return res
+}
@@ -0,0 +1,82 @@
+package fetch_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.i3wm.org/i3/v4"
+)
+
+func TestParseSwayTree(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "sway_get_tree.json"))
+	require.NoError(t, err)
+
+	got, gotErr := fetch.ParseSwayTree("sway", data, nil)
+	require.Nil(t, gotErr)
+	require.NotNil(t, got.Root)
+
+	ws := got.Root.Nodes[1].Nodes[0]
+	assert.Equal(t, "1", ws.Name)
+	assert.Equal(t, i3.NodeType(i3.WorkspaceNode), ws.Type)
+
+	// Wayland native windows show their app_id as class
+	native := ws.Nodes[0]
+	assert.Equal(t, "foot", native.WindowProperties.Class)
+	assert.Equal(t, "nvim ~/src", native.WindowProperties.Title)
+	assert.True(t, native.Focused)
+
+	// xwayland windows keep their X11 class
+	xwayland := ws.Nodes[1]
+	assert.Equal(t, "Slack", xwayland.WindowProperties.Class)
+	assert.Equal(t, "slack", xwayland.WindowProperties.Instance)
+	assert.Equal(t, []string{"chat"}, xwayland.Marks)
+
+	floating := ws.FloatingNodes[0]
+	assert.Equal(t, "firefox", floating.WindowProperties.Class)
+	assert.Equal(t, i3.Rect{X: 1900, Y: 1000, Width: 640, Height: 360}, floating.Rect)
+}
+
+func TestParseSwayTreeExtras(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "sway_get_tree.json"))
+	require.NoError(t, err)
+
+	visible := true
+	extras := fetch.Extras{42: {PID: 1}}
+	_, gotErr := fetch.ParseSwayTree("sway", data, extras)
+	require.Nil(t, gotErr)
+
+	// the nodes of a previous tree are forgotten
+	assert.Equal(t, fetch.Extras{
+		4: {Visible: &visible},
+		5: {PID: 4242, Visible: &visible},
+		6: {PID: 5151, Visible: &visible, InhibitIdle: true},
+		7: {PID: 6262, Visible: &visible, InhibitIdle: true},
+	}, extras)
+}
+
+func TestParseSwayTreeError(t *testing.T) {
+	_, gotErr := fetch.ParseSwayTree("sway", []byte(`{"nodes": [}`), nil)
+
+	var parseErr fetch.ParseError
+	require.ErrorAs(t, gotErr, &parseErr)
+	assert.Equal(t, 1, parseErr.Line)
+	assert.Equal(t, 12, parseErr.Column)
+}
+
+func TestFromSwayNoSocket(t *testing.T) {
+	f := fetch.FromSway{SocketPath: filepath.Join(t.TempDir(), "missing.sock")}
+	_, gotErr := f.Fetch()
+
+	assert.Error(t, gotErr)
+}
+
+func TestFromSwayOutputsNoSocket(t *testing.T) {
+	f := fetch.FromSway{SocketPath: filepath.Join(t.TempDir(), "missing.sock")}
+	_, gotErr := f.Outputs()
+
+	assert.Error(t, gotErr)
+}
@@ -7,13 +7,32 @@ import (
 type Fetcher interface {
 	Fetch() (i3.Tree, error)
 }
+
+// Pruner keeps the part of a tree that should be rendered
+// it returns a new tree and never modifies the one it's given,
+// so a single fetched tree can be pruned several times
 type Pruner interface {
 	Prune(*i3.Tree) *i3.Tree
 }
+
+// StrictPruner is a Pruner that can fail, e.g. when nothing matches what was asked for
+// View uses TryPrune for the pruners implementing it
+type StrictPruner interface {
+	Pruner
+	TryPrune(*i3.Tree) (*i3.Tree, error)
+}
+
 type Renderer interface {
 	Render(*i3.Tree)
 }
 
+// StrictRenderer is a Renderer that can fail, e.g. when a template can't be executed
+// View uses TryRender for the renderers implementing it
+type StrictRenderer interface {
+	Renderer
+	TryRender(*i3.Tree) error
+}
+
 type i3TreeViewer struct {
 	Fetcher
 	Pruner
@@ -34,8 +53,27 @@ func (i3tv *i3TreeViewer) View() error {
 		// TODO
 		return err
 	}
-	n := i3tv.Prune(&tree)
+	n, err := TryPrune(i3tv.Pruner, &tree)
+	if err != nil {
+		return err
+	}
+
+	return TryRender(i3tv.Renderer, n)
+}
 
-	i3tv.Render(n)
+// TryPrune prunes a tree, failing only when the pruner is a StrictPruner that fails
+func TryPrune(p Pruner, tree *i3.Tree) (*i3.Tree, error) {
+	if sp, ok := p.(StrictPruner); ok {
+		return sp.TryPrune(tree)
+	}
+	return p.Prune(tree), nil
+}
+
+// TryRender renders a tree, failing only when the renderer is a StrictRenderer that fails
+func TryRender(r Renderer, tree *i3.Tree) error {
+	if sr, ok := r.(StrictRenderer); ok {
+		return sr.TryRender(tree)
+	}
+	r.Render(tree)
 	return nil
 }
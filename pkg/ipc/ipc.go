@@ -0,0 +1,218 @@
+// Package ipc is a minimal client for the i3 IPC protocol
+// It is used to talk to sway, which go-i3 can't find,
+// and to get the raw replies of i3, with the fields go-i3 doesn't decode
+// See https://i3wm.org/docs/ipc.html
+package ipc
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+// MessageType is the type of a message sent to the window manager
+type MessageType uint32
+
+const (
+	RunCommand    MessageType = 0
+	GetWorkspaces MessageType = 1
+	Subscribe     MessageType = 2
+	GetOutputs    MessageType = 3
+	GetTree       MessageType = 4
+)
+
+const magic = "i3-ipc"
+
+// eventMask is set in the type of messages that are events
+const eventMask = uint32(1) << 31
+
+// Conn is a connection to the IPC socket
+type Conn struct {
+	conn net.Conn
+}
+
+// Dial connects to the IPC socket at path
+func Dial(path string) (*Conn, error) {
+	if path == "" {
+		return nil, errors.New("no IPC socket path")
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Conn{conn}, nil
+}
+
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// Request sends a message and returns the payload of its reply
+func (c *Conn) Request(t MessageType, payload []byte) ([]byte, error) {
+	if err := c.write(t, payload); err != nil {
+		return nil, err
+	}
+
+	for {
+		msgType, reply, err := c.read()
+		if err != nil {
+			return nil, err
+		}
+
+		// events may arrive in between if the connection is subscribed
+		if msgType&eventMask != 0 {
+			continue
+		}
+
+		if msgType != uint32(t) {
+			return nil, fmt.Errorf("unexpected reply type %d to message type %d", msgType, t)
+		}
+
+		return reply, nil
+	}
+}
+
+func (c *Conn) write(t MessageType, payload []byte) error {
+	msg := make([]byte, 0, len(magic)+8+len(payload))
+	msg = append(msg, magic...)
+	msg = appendUint32(msg, uint32(len(payload)))
+	msg = appendUint32(msg, uint32(t))
+	msg = append(msg, payload...)
+
+	_, err := c.conn.Write(msg)
+	return err
+}
+
+func (c *Conn) read() (uint32, []byte, error) {
+	header := make([]byte, len(magic)+8)
+	if _, err := io.ReadFull(c.conn, header); err != nil {
+		return 0, nil, err
+	}
+
+	if string(header[:len(magic)]) != magic {
+		return 0, nil, fmt.Errorf("invalid IPC magic %q", header[:len(magic)])
+	}
+
+	length := binary.LittleEndian.Uint32(header[len(magic):])
+	msgType := binary.LittleEndian.Uint32(header[len(magic)+4:])
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.conn, payload); err != nil {
+		return 0, nil, err
+	}
+
+	return msgType, payload, nil
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+// EventReceiver delivers the events a connection subscribed to
+// It mirrors go-i3's EventReceiver: Next returns false on error
+// and Close then returns that error
+type EventReceiver struct {
+	conn    *Conn
+	payload []byte
+
+	// Close may be called while Next is blocked
+	mu  sync.Mutex
+	err error
+}
+
+// SubscribeTo connects to path and subscribes to events (e.g. "window", "workspace")
+func SubscribeTo(path string, events ...string) *EventReceiver {
+	r := &EventReceiver{}
+
+	conn, err := Dial(path)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	r.conn = conn
+
+	payload, err := json.Marshal(events)
+	if err != nil {
+		r.err = err
+		return r
+	}
+
+	reply, err := conn.Request(Subscribe, payload)
+	if err != nil {
+		r.err = err
+		return r
+	}
+
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := json.Unmarshal(reply, &result); err != nil {
+		r.err = err
+		return r
+	}
+	if !result.Success {
+		r.err = fmt.Errorf("could not subscribe to %v", events)
+	}
+
+	return r
+}
+
+// Next blocks until the next event arrives
+func (r *EventReceiver) Next() bool {
+	if r.getErr() != nil {
+		return false
+	}
+
+	for {
+		msgType, payload, err := r.conn.read()
+		if err != nil {
+			r.setErr(err)
+			return false
+		}
+
+		if msgType&eventMask != 0 {
+			r.payload = payload
+			return true
+		}
+	}
+}
+
+// Payload returns the raw JSON of the last event
+func (r *EventReceiver) Payload() []byte {
+	return r.payload
+}
+
+// Close closes the connection and returns the first error that happened, if any
+func (r *EventReceiver) Close() error {
+	if r.conn != nil {
+		r.conn.Close()
+	}
+
+	err := r.getErr()
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+func (r *EventReceiver) getErr() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *EventReceiver) setErr(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = err
+	}
+}
@@ -0,0 +1,105 @@
+package ipc_test
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/ipc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWM answers every request with reply
+// and then sends events to the client
+func fakeWM(t *testing.T, reply string, events ...string) string {
+	path := filepath.Join(t.TempDir(), "ipc.sock")
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		header := make([]byte, 14)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		payload := make([]byte, binary.LittleEndian.Uint32(header[6:]))
+		if _, err := io.ReadFull(conn, payload); err != nil {
+			return
+		}
+		msgType := binary.LittleEndian.Uint32(header[10:])
+
+		writeMsg(conn, msgType, reply)
+		for _, e := range events {
+			writeMsg(conn, msgType|1<<31, e)
+		}
+	}()
+
+	return path
+}
+
+func writeMsg(w io.Writer, msgType uint32, payload string) {
+	msg := make([]byte, 14, 14+len(payload))
+	copy(msg, "i3-ipc")
+	binary.LittleEndian.PutUint32(msg[6:], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(msg[10:], msgType)
+	w.Write(append(msg, payload...))
+}
+
+func TestRequest(t *testing.T) {
+	path := fakeWM(t, `{"id":1,"type":"root"}`)
+
+	conn, err := ipc.Dial(path)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	got, gotErr := conn.Request(ipc.GetTree, nil)
+
+	assert.Nil(t, gotErr)
+	assert.Equal(t, `{"id":1,"type":"root"}`, string(got))
+}
+
+func TestDialNoPath(t *testing.T) {
+	_, gotErr := ipc.Dial("")
+
+	assert.Error(t, gotErr)
+}
+
+func TestSubscribeTo(t *testing.T) {
+	path := fakeWM(t, `{"success":true}`, `{"change":"new"}`, `{"change":"close"}`)
+
+	r := ipc.SubscribeTo(path, "window")
+
+	require.True(t, r.Next())
+	assert.Equal(t, `{"change":"new"}`, string(r.Payload()))
+	require.True(t, r.Next())
+	assert.Equal(t, `{"change":"close"}`, string(r.Payload()))
+
+	// the fake closes the connection after the last event
+	assert.False(t, r.Next())
+	assert.Equal(t, io.EOF, r.Close())
+}
+
+func TestSubscribeToRefused(t *testing.T) {
+	path := fakeWM(t, `{"success":false}`)
+
+	r := ipc.SubscribeTo(path, "window")
+
+	assert.False(t, r.Next())
+	assert.Error(t, r.Close())
+}
+
+func TestSubscribeToNoSocket(t *testing.T) {
+	r := ipc.SubscribeTo(filepath.Join(t.TempDir(), "missing.sock"), "window")
+
+	assert.False(t, r.Next())
+	assert.Error(t, r.Close())
+}
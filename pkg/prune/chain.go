@@ -0,0 +1,42 @@
+package prune
+
+import (
+	"github.com/njhoffman/i3-tree/pkg/i3treeviewer"
+	"go.i3wm.org/i3/v4"
+)
+
+// Chain applies pruners in order, each stage pruning what the previous one kept
+// As soon as a stage leaves an empty tree, the chain stops and returns an empty tree:
+// stages never receive a tree without a root
+type Chain struct {
+	Stages []i3treeviewer.Pruner
+}
+
+func (c *Chain) Prune(tree *i3.Tree) *i3.Tree {
+	t, _ := c.TryPrune(tree)
+	return t
+}
+
+// TryPrune stops at the first stage that fails, returning its error
+func (c *Chain) TryPrune(tree *i3.Tree) (*i3.Tree, error) {
+	if len(c.Stages) == 0 {
+		return Copy(tree), nil
+	}
+
+	for _, s := range c.Stages {
+		if tree == nil || tree.Root == nil {
+			return &i3.Tree{}, nil
+		}
+
+		var err error
+		tree, err = i3treeviewer.TryPrune(s, tree)
+		if err != nil {
+			return &i3.Tree{}, err
+		}
+	}
+
+	if tree == nil || tree.Root == nil {
+		return &i3.Tree{}, nil
+	}
+	return tree, nil
+}
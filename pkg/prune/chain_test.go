@@ -0,0 +1,81 @@
+package prune_test
+
+import (
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/i3treeviewer"
+	"github.com/njhoffman/i3-tree/pkg/prune"
+	"go.i3wm.org/i3/v4"
+	"gotest.tools/assert"
+)
+
+// countingPruner records how many times it was called
+type countingPruner struct {
+	calls int
+}
+
+func (p *countingPruner) Prune(tree *i3.Tree) *i3.Tree {
+	p.calls++
+	return tree
+}
+
+func TestChain(t *testing.T) {
+	t.Run("stages are applied in order", func(t *testing.T) {
+		floating, err := prune.NewFilter("floating")
+		assert.NilError(t, err)
+
+		c := &prune.Chain{Stages: []i3treeviewer.Pruner{
+			&prune.Ws{WsIndex: "1"},
+			floating,
+		}}
+		got := c.Prune(filterTree())
+
+		assert.DeepEqual(t, []string{"Mozilla Firefox"}, names(got.Root))
+		assert.Equal(t, got.Root.Nodes[0].Name, "DP-1")
+	})
+
+	t.Run("no stages", func(t *testing.T) {
+		c := &prune.Chain{}
+		tree := filterTree()
+
+		assert.DeepEqual(t, tree, c.Prune(tree))
+	})
+
+	t.Run("empty tree stops the chain", func(t *testing.T) {
+		last := &countingPruner{}
+
+		c := &prune.Chain{Stages: []i3treeviewer.Pruner{
+			&prune.Ws{WsIndex: "nope"},
+			last,
+		}}
+		got := c.Prune(filterTree())
+
+		assert.DeepEqual(t, &i3.Tree{}, got)
+		assert.Equal(t, 0, last.calls)
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		first := &countingPruner{}
+
+		c := &prune.Chain{Stages: []i3treeviewer.Pruner{first}}
+		got := c.Prune(&i3.Tree{})
+
+		assert.DeepEqual(t, &i3.Tree{}, got)
+		assert.Equal(t, 0, first.calls)
+	})
+}
+
+func TestChainTryPrune(t *testing.T) {
+	last := &countingPruner{}
+
+	c := &prune.Chain{Stages: []i3treeviewer.Pruner{
+		&prune.NonEmptyWs{},
+		&prune.Ws{WsIndex: "9"},
+		last,
+	}}
+	got, err := c.TryPrune(filterTree())
+
+	assert.DeepEqual(t, &i3.Tree{}, got)
+	assert.ErrorType(t, err, prune.NoWorkspaceError{})
+	assert.Equal(t, 0, last.calls)
+}
@@ -0,0 +1,54 @@
+package prune
+
+import "go.i3wm.org/i3/v4"
+
+// Copy deep copies a tree, so it can be modified
+// without changing the one it was copied from
+func Copy(tree *i3.Tree) *i3.Tree {
+	if tree == nil || tree.Root == nil {
+		return &i3.Tree{}
+	}
+
+	return &i3.Tree{
+		Root: copyNode(tree.Root),
+	}
+}
+
+// copyNode deep copies a node and everything within it
+func copyNode(node *i3.Node) *i3.Node {
+	c := withChildren(node, nil, nil)
+
+	if node.Nodes != nil {
+		c.Nodes = make([]*i3.Node, len(node.Nodes))
+		for i, n := range node.Nodes {
+			c.Nodes[i] = copyNode(n)
+		}
+	}
+	if node.FloatingNodes != nil {
+		c.FloatingNodes = make([]*i3.Node, len(node.FloatingNodes))
+		for i, n := range node.FloatingNodes {
+			c.FloatingNodes[i] = copyNode(n)
+		}
+	}
+
+	return c
+}
+
+// withChildren copies a node with other children
+// the copy shares nothing with the original, but the given children
+func withChildren(node *i3.Node, nodes []*i3.Node, floatingNodes []*i3.Node) *i3.Node {
+	c := *node
+	c.Nodes = nodes
+	c.FloatingNodes = floatingNodes
+
+	if node.Marks != nil {
+		c.Marks = make([]string, len(node.Marks))
+		copy(c.Marks, node.Marks)
+	}
+	if node.Focus != nil {
+		c.Focus = make([]i3.NodeID, len(node.Focus))
+		copy(c.Focus, node.Focus)
+	}
+
+	return &c
+}
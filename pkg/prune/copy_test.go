@@ -0,0 +1,108 @@
+package prune_test
+
+import (
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/njhoffman/i3-tree/pkg/i3treeviewer"
+	"github.com/njhoffman/i3-tree/pkg/prune"
+	"go.i3wm.org/i3/v4"
+	"gotest.tools/assert"
+)
+
+func TestCopy(t *testing.T) {
+	t.Run("empty tree", func(t *testing.T) {
+		assert.DeepEqual(t, &i3.Tree{}, prune.Copy(&i3.Tree{}))
+		assert.DeepEqual(t, &i3.Tree{}, prune.Copy(nil))
+	})
+
+	t.Run("copies share nothing", func(t *testing.T) {
+		tree := filterTree()
+		got := prune.Copy(tree)
+
+		assert.DeepEqual(t, filterTree(), got)
+
+		scribble(got.Root)
+		assert.DeepEqual(t, filterTree(), tree)
+	})
+}
+
+// TestPrunersDontModifyTheirInput prunes a single tree with every strategy
+// checking it is unchanged, and that pruning it again gives the same result
+func TestPrunersDontModifyTheirInput(t *testing.T) {
+	filter, err := prune.NewFilter("class=Firefox or urgent")
+	assert.NilError(t, err)
+
+	pruners := []struct {
+		name   string
+		pruner i3treeviewer.Pruner
+	}{
+		{"focused", &prune.FocusedWs{}},
+		{"non empty", &prune.NonEmptyWs{}},
+		{"ws", &prune.Ws{WsIndex: "1"}},
+		{"noop", &prune.NoOp{}},
+		{"filter", filter},
+		{"depth", &prune.Depth{Max: 1}},
+		{"output", &prune.Output{Pattern: "*"}},
+		{"visible", &prune.Visible{Lister: fetch.FromFake{}}},
+		{"scratch", &prune.Scratch{}},
+		{"simplify", &prune.Simplify{}},
+		{"match", &prune.Match{Filter: filter, Context: 1}},
+		{"chain", &prune.Chain{Stages: []i3treeviewer.Pruner{&prune.NonEmptyWs{}, filter, &prune.Depth{Max: 1}}}},
+		{"empty chain", &prune.Chain{}},
+	}
+
+	trees := []struct {
+		name  string
+		fresh func() *i3.Tree
+	}{
+		{"filter tree", filterTree},
+		{"scratch tree", func() *i3.Tree { return scratchTree("KeePassXC") }},
+		{"mock", func() *i3.Tree {
+			tree, err := fetch.FromFake{}.Fetch()
+			assert.NilError(t, err)
+			return &tree
+		}},
+	}
+
+	for _, tr := range trees {
+		for _, p := range pruners {
+			t.Run(tr.name+"/"+p.name, func(t *testing.T) {
+				tree := tr.fresh()
+
+				first := p.pruner.Prune(tree)
+				assert.DeepEqual(t, tr.fresh(), tree)
+
+				second := p.pruner.Prune(tree)
+				assert.DeepEqual(t, first, second)
+
+				// changing what was pruned doesn't change the input either
+				scribble(first.Root)
+				assert.DeepEqual(t, tr.fresh(), tree)
+				assert.DeepEqual(t, second, p.pruner.Prune(tree))
+			})
+		}
+	}
+}
+
+// scribble modifies everything in a tree
+func scribble(node *i3.Node) {
+	if node == nil {
+		return
+	}
+
+	node.Name = "scribbled"
+	for i := range node.Marks {
+		node.Marks[i] = "scribbled"
+	}
+	for i := range node.Focus {
+		node.Focus[i] = -1
+	}
+	for _, n := range node.Nodes {
+		scribble(n)
+	}
+	for _, n := range node.FloatingNodes {
+		scribble(n)
+	}
+	node.Nodes = append(node.Nodes, &i3.Node{Name: "added"})
+}
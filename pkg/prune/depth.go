@@ -0,0 +1,43 @@
+package prune
+
+import "go.i3wm.org/i3/v4"
+
+// Depth keeps containers up to Max levels below their workspace
+// Nodes outside of workspaces, such as the root and outputs, are kept as they are
+type Depth struct {
+	Max int
+}
+
+func (d *Depth) Prune(tree *i3.Tree) *i3.Tree {
+	if tree.Root == nil {
+		return &i3.Tree{}
+	}
+
+	return &i3.Tree{
+		Root: d.cut(tree.Root, -1),
+	}
+}
+
+// cut copies node, dropping what's deeper than Max
+// level is -1 until a workspace is found
+func (d *Depth) cut(node *i3.Node, level int) *i3.Node {
+	if node.Type == "workspace" {
+		level = 0
+	} else if level >= 0 {
+		level++
+	}
+
+	c := withChildren(node, nil, nil)
+	if level >= d.Max {
+		return c
+	}
+
+	for _, n := range node.Nodes {
+		c.Nodes = append(c.Nodes, d.cut(n, level))
+	}
+	for _, n := range node.FloatingNodes {
+		c.FloatingNodes = append(c.FloatingNodes, d.cut(n, level))
+	}
+
+	return c
+}
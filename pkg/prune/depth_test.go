@@ -0,0 +1,46 @@
+package prune_test
+
+import (
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/prune"
+	"go.i3wm.org/i3/v4"
+	"gotest.tools/assert"
+)
+
+func TestDepth(t *testing.T) {
+	t.Run("empty tree", func(t *testing.T) {
+		d := &prune.Depth{Max: 1}
+
+		assert.DeepEqual(t, &i3.Tree{}, d.Prune(&i3.Tree{}))
+	})
+
+	t.Run("workspaces only", func(t *testing.T) {
+		d := &prune.Depth{Max: 0}
+		got := d.Prune(filterTree())
+
+		assert.DeepEqual(t, []string(nil), names(got.Root))
+		ws := got.Root.Nodes[0].Nodes[0]
+		assert.Equal(t, "workspace", string(ws.Type))
+		assert.Assert(t, ws.Nodes == nil && ws.FloatingNodes == nil)
+	})
+
+	t.Run("one level below workspaces", func(t *testing.T) {
+		d := &prune.Depth{Max: 1}
+		got := d.Prune(filterTree())
+
+		ws := got.Root.Nodes[0].Nodes[0]
+		assert.Equal(t, 2, len(ws.Nodes))
+		assert.Equal(t, 1, len(ws.FloatingNodes))
+		// the tabbed container is kept, but not what's within it
+		assert.Equal(t, "tabbed", string(ws.Nodes[1].Layout))
+		assert.Equal(t, 0, len(ws.Nodes[1].Nodes))
+		assert.Equal(t, 0, len(ws.FloatingNodes[0].Nodes))
+	})
+
+	t.Run("deep enough to keep everything", func(t *testing.T) {
+		d := &prune.Depth{Max: 5}
+
+		assert.DeepEqual(t, filterTree(), d.Prune(filterTree()))
+	})
+}
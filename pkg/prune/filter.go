@@ -0,0 +1,576 @@
+package prune
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go.i3wm.org/i3/v4"
+)
+
+// Filter keeps the windows matching an expression, along with their ancestors
+//
+// Expressions compare node fields with an operator:
+//
+//	class=Firefox            equal
+//	title!="Mozilla Firefox" not equal
+//	title~/jira/i            regular expression, i makes it case insensitive
+//	instance!~term           regular expression not matching
+//
+// String fields are class, instance, title, mark, type, layout, output and workspace
+// mark matches when any of the marks does, layout is the one of the container
+// the window is in, output and workspace are the names of the ones it is in
+// Boolean fields urgent, focused, fullscreen and floating can be used on their own
+// or compared with true and false
+//
+// Comparisons are combined with not, and, or (by decreasing precedence) and parentheses
+// Containers only match an expression comparing their type, e.g. type=workspace and workspace=1,
+// a matching container is then kept whole, with everything within it
+type Filter struct {
+	Expr  string
+	root  filterExpr
+	typed bool
+}
+
+// NewFilter parses expr, failing with a FilterError
+func NewFilter(expr string) (*Filter, error) {
+	p := filterParser{expr: expr}
+
+	root, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Filter{Expr: expr, root: root, typed: p.typed}, nil
+}
+
+func (f *Filter) Prune(tree *i3.Tree) *i3.Tree {
+	ctx := newFilterContexts(tree.Root)
+
+	return pruneSubtree(tree, func(src *i3.Node) bool {
+		return f.match(src, ctx[src])
+	})
+}
+
+// Matching returns the nodes of tree matching the expression
+func (f *Filter) Matching(tree *i3.Tree) map[*i3.Node]bool {
+	matching := make(map[*i3.Node]bool)
+	for node, ctx := range newFilterContexts(tree.Root) {
+		if f.match(node, ctx) {
+			matching[node] = true
+		}
+	}
+	return matching
+}
+
+// match leaves out the containers, unless the expression compares the type
+func (f *Filter) match(node *i3.Node, ctx filterContext) bool {
+	if !f.typed && !IsWindow(node) {
+		return false
+	}
+	return f.root.match(node, ctx)
+}
+
+// IsFilter tells if arg is meant as a filter expression rather than a workspace name
+// which is when it holds an operator, or starts with not or a parenthesis
+func IsFilter(arg string) bool {
+	arg = strings.TrimSpace(arg)
+	if strings.HasPrefix(arg, "(") || strings.ContainsAny(arg, "=~") {
+		return true
+	}
+
+	return strings.ToLower(leadingWord(arg)) == "not"
+}
+
+// IsStageFilter is IsFilter for the stages of a prune chain, where a workspace name is unlikely,
+// so a boolean field on its own, e.g. floating or urgent, is a filter too
+func IsStageFilter(arg string) bool {
+	return IsFilter(arg) || filterFields[strings.ToLower(strings.TrimSpace(arg))] == boolField
+}
+
+// FilterError points at the column of a filter expression that could not be parsed
+type FilterError struct {
+	Expr   string
+	Column int
+	Msg    string
+}
+
+func (e FilterError) Error() string {
+	return fmt.Sprintf(
+		"invalid filter at column %d: %s\n  %s\n  %s^",
+		e.Column, e.Msg, e.Expr, strings.Repeat(" ", e.Column-1),
+	)
+}
+
+// filterContext is what a node can't tell about itself
+type filterContext struct {
+	output    string
+	workspace string
+	layout    string
+	floating  bool
+}
+
+func newFilterContexts(root *i3.Node) map[*i3.Node]filterContext {
+	contexts := make(map[*i3.Node]filterContext)
+
+	var walk func(node *i3.Node, ctx filterContext)
+	walk = func(node *i3.Node, ctx filterContext) {
+		switch node.Type {
+		case "output":
+			ctx.output = node.Name
+		case "workspace":
+			ctx.workspace = node.Name
+		case "floating_con":
+			ctx.floating = true
+		}
+		contexts[node] = ctx
+
+		ctx.layout = string(node.Layout)
+		for _, n := range node.Nodes {
+			walk(n, ctx)
+		}
+		for _, n := range node.FloatingNodes {
+			walk(n, ctx)
+		}
+	}
+
+	if root != nil {
+		walk(root, filterContext{})
+	}
+
+	return contexts
+}
+
+type filterFieldKind int
+
+const (
+	stringField filterFieldKind = iota
+	boolField
+)
+
+var filterFields = map[string]filterFieldKind{
+	"class":      stringField,
+	"instance":   stringField,
+	"title":      stringField,
+	"mark":       stringField,
+	"type":       stringField,
+	"layout":     stringField,
+	"output":     stringField,
+	"workspace":  stringField,
+	"urgent":     boolField,
+	"focused":    boolField,
+	"fullscreen": boolField,
+	"floating":   boolField,
+}
+
+// fieldStrings returns the values of a string field, mark being the only one with several
+func fieldStrings(field string, node *i3.Node, ctx filterContext) []string {
+	switch field {
+	case "class":
+		return []string{node.WindowProperties.Class}
+	case "instance":
+		return []string{node.WindowProperties.Instance}
+	case "title":
+		return []string{node.Name}
+	case "mark":
+		return node.Marks
+	case "type":
+		return []string{string(node.Type)}
+	case "layout":
+		if IsWindow(node) {
+			return []string{ctx.layout}
+		}
+		return []string{string(node.Layout)}
+	case "output":
+		return []string{ctx.output}
+	case "workspace":
+		return []string{ctx.workspace}
+	default:
+		return nil
+	}
+}
+
+func fieldBool(field string, node *i3.Node, ctx filterContext) bool {
+	switch field {
+	case "urgent":
+		return node.Urgent
+	case "focused":
+		return node.Focused
+	case "fullscreen":
+		return node.FullscreenMode != 0
+	case "floating":
+		return ctx.floating
+	default:
+		return false
+	}
+}
+
+type filterExpr interface {
+	match(node *i3.Node, ctx filterContext) bool
+}
+
+type orExpr struct{ left, right filterExpr }
+
+func (e orExpr) match(node *i3.Node, ctx filterContext) bool {
+	return e.left.match(node, ctx) || e.right.match(node, ctx)
+}
+
+type andExpr struct{ left, right filterExpr }
+
+func (e andExpr) match(node *i3.Node, ctx filterContext) bool {
+	return e.left.match(node, ctx) && e.right.match(node, ctx)
+}
+
+type notExpr struct{ expr filterExpr }
+
+func (e notExpr) match(node *i3.Node, ctx filterContext) bool {
+	return !e.expr.match(node, ctx)
+}
+
+type boolExpr struct {
+	field string
+	want  bool
+}
+
+func (e boolExpr) match(node *i3.Node, ctx filterContext) bool {
+	return fieldBool(e.field, node, ctx) == e.want
+}
+
+type stringExpr struct {
+	field  string
+	value  string
+	re     *regexp.Regexp
+	negate bool
+}
+
+func (e stringExpr) match(node *i3.Node, ctx filterContext) bool {
+	found := false
+	for _, v := range fieldStrings(e.field, node, ctx) {
+		if e.re != nil && e.re.MatchString(v) || e.re == nil && v == e.value {
+			found = true
+			break
+		}
+	}
+
+	return found != e.negate
+}
+
+// filterParser is a recursive descent parser over the expression
+//
+//	or   = and { "or" and }
+//	and  = not { "and" not }
+//	not  = "not" not | "(" or ")" | comparison
+type filterParser struct {
+	expr  string
+	pos   int
+	typed bool
+}
+
+func (p *filterParser) parse() (filterExpr, error) {
+	p.skipSpace()
+	if p.pos == len(p.expr) {
+		return nil, p.errorf(p.pos, "empty filter")
+	}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if p.pos < len(p.expr) {
+		return nil, p.errorf(p.pos, "unexpected %q, expected and/or", p.expr[p.pos:])
+	}
+
+	return e, nil
+}
+
+func (p *filterParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.acceptKeyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.acceptKeyword("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseNot() (filterExpr, error) {
+	if p.acceptKeyword("not") {
+		e, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{e}, nil
+	}
+
+	p.skipSpace()
+	if p.pos < len(p.expr) && p.expr[p.pos] == '(' {
+		open := p.pos
+		p.pos++
+
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpace()
+		if p.pos == len(p.expr) || p.expr[p.pos] != ')' {
+			return nil, p.errorf(open, "unclosed parenthesis")
+		}
+		p.pos++
+
+		return e, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterExpr, error) {
+	p.skipSpace()
+	start := p.pos
+
+	field := strings.ToLower(p.word())
+	if field == "" {
+		if p.pos == len(p.expr) {
+			return nil, p.errorf(p.pos, "unexpected end of filter, expected a field")
+		}
+		return nil, p.errorf(p.pos, "expected a field, found %q", string(p.peek()))
+	}
+
+	kind, ok := filterFields[field]
+	if !ok {
+		return nil, p.errorf(start, "unknown field %q", field)
+	}
+	if field == "type" {
+		p.typed = true
+	}
+
+	opPos := p.pos
+	op := p.operator()
+
+	if kind == boolField {
+		switch op {
+		case "":
+			return boolExpr{field: field, want: true}, nil
+		case "=", "!=":
+			valuePos := p.pos
+			v := strings.ToLower(p.word())
+			if v != "true" && v != "false" {
+				return nil, p.errorf(valuePos, "%s is true or false", field)
+			}
+			return boolExpr{field: field, want: (v == "true") == (op == "=")}, nil
+		default:
+			return nil, p.errorf(opPos, "%s can only be compared with = or !=", field)
+		}
+	}
+
+	if op == "" {
+		p.skipSpace()
+		return nil, p.errorf(p.pos, "expected =, !=, ~ or !~ after %s", field)
+	}
+
+	valuePos := p.pos
+	e := stringExpr{field: field, negate: op[0] == '!'}
+
+	if op == "~" || op == "!~" {
+		pattern, err := p.regexValue()
+		if err != nil {
+			return nil, err
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, p.errorf(valuePos, "invalid regular expression: %s", err)
+		}
+		e.re = re
+		return e, nil
+	}
+
+	v, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	e.value = v
+
+	return e, nil
+}
+
+// operator reads a comparison operator, if there's one
+func (p *filterParser) operator() string {
+	for _, op := range []string{"!=", "!~", "=", "~"} {
+		if strings.HasPrefix(p.expr[p.pos:], op) {
+			p.pos += len(op)
+			return op
+		}
+	}
+	return ""
+}
+
+// value reads a quoted string or a word
+func (p *filterParser) value() (string, error) {
+	if p.pos < len(p.expr) && p.expr[p.pos] == '"' {
+		return p.quoted()
+	}
+
+	start := p.pos
+	for p.pos < len(p.expr) {
+		r, size := utf8.DecodeRuneInString(p.expr[p.pos:])
+		if unicode.IsSpace(r) || r == '(' || r == ')' {
+			break
+		}
+		p.pos += size
+	}
+
+	if p.pos == start {
+		return "", p.errorf(start, "expected a value")
+	}
+
+	return p.expr[start:p.pos], nil
+}
+
+// quoted reads a double quoted string, where \ escapes the next character
+func (p *filterParser) quoted() (string, error) {
+	start := p.pos
+	p.pos++
+
+	var sb strings.Builder
+	for p.pos < len(p.expr) {
+		c := p.expr[p.pos]
+		switch {
+		case c == '\\' && p.pos+1 < len(p.expr):
+			sb.WriteByte(p.expr[p.pos+1])
+			p.pos += 2
+		case c == '"':
+			p.pos++
+			return sb.String(), nil
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	return "", p.errorf(start, "unclosed quote")
+}
+
+// regexValue reads /pattern/flags, or a value used as the pattern
+// the only flag is i, for case insensitive matching
+func (p *filterParser) regexValue() (string, error) {
+	if p.pos == len(p.expr) || p.expr[p.pos] != '/' {
+		return p.value()
+	}
+
+	start := p.pos
+	p.pos++
+
+	var sb strings.Builder
+	closed := false
+	for p.pos < len(p.expr) && !closed {
+		c := p.expr[p.pos]
+		switch {
+		case c == '\\' && p.pos+1 < len(p.expr) && p.expr[p.pos+1] == '/':
+			sb.WriteByte('/')
+			p.pos += 2
+		case c == '/':
+			closed = true
+			p.pos++
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+	if !closed {
+		return "", p.errorf(start, "unclosed regular expression")
+	}
+
+	pattern := sb.String()
+	for p.pos < len(p.expr) && unicode.IsLetter(p.peek()) {
+		if flag := p.peek(); flag != 'i' {
+			return "", p.errorf(p.pos, "unknown regular expression flag %q", string(flag))
+		}
+		pattern = "(?i)" + strings.TrimPrefix(pattern, "(?i)")
+		p.pos++
+	}
+
+	return pattern, nil
+}
+
+// acceptKeyword consumes keyword if it's the next word
+func (p *filterParser) acceptKeyword(keyword string) bool {
+	p.skipSpace()
+
+	rest := p.expr[p.pos:]
+	word := leadingWord(rest)
+	if strings.ToLower(word) != keyword {
+		return false
+	}
+
+	p.pos += len(word)
+	return true
+}
+
+// word reads letters and underscores
+func (p *filterParser) word() string {
+	w := leadingWord(p.expr[p.pos:])
+	p.pos += len(w)
+	return w
+}
+
+// peek returns the rune at pos, utf8.RuneError at the end of the expression
+func (p *filterParser) peek() rune {
+	r, _ := utf8.DecodeRuneInString(p.expr[p.pos:])
+	return r
+}
+
+func (p *filterParser) skipSpace() {
+	for p.pos < len(p.expr) {
+		r, size := utf8.DecodeRuneInString(p.expr[p.pos:])
+		if !unicode.IsSpace(r) {
+			return
+		}
+		p.pos += size
+	}
+}
+
+// errorf creates a FilterError at the byte offset pos
+func (p *filterParser) errorf(pos int, format string, args ...interface{}) error {
+	return FilterError{
+		Expr:   p.expr,
+		Column: utf8.RuneCountInString(p.expr[:pos]) + 1,
+		Msg:    fmt.Sprintf(format, args...),
+	}
+}
+
+// leadingWord is the ascii letters and underscores s starts with
+func leadingWord(s string) string {
+	i := 0
+	for i < len(s) && (s[i] >= 'a' && s[i] <= 'z' || s[i] >= 'A' && s[i] <= 'Z' || s[i] == '_') {
+		i++
+	}
+	return s[:i]
+}
@@ -0,0 +1,280 @@
+package prune_test
+
+import (
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/prune"
+	"go.i3wm.org/i3/v4"
+	"gotest.tools/assert"
+)
+
+func filterTree() *i3.Tree {
+	return &i3.Tree{
+		Root: &i3.Node{
+			Type: "root",
+			Nodes: []*i3.Node{
+				{
+					Name: "DP-1",
+					Type: "output",
+					Nodes: []*i3.Node{
+						{
+							Name: "1",
+							Type: "workspace",
+							Nodes: []*i3.Node{
+								{
+									Type:             "con",
+									Name:             "Jira - Mozilla Firefox",
+									WindowProperties: i3.WindowProperties{Class: "Firefox"},
+								},
+								{
+									Type:   "con",
+									Layout: "tabbed",
+									Nodes: []*i3.Node{
+										{
+											Type:             "con",
+											Name:             "vim",
+											Focused:          true,
+											Marks:            []string{"edit"},
+											WindowProperties: i3.WindowProperties{Class: "Alacritty", Instance: "term"},
+										},
+									},
+								},
+							},
+							FloatingNodes: []*i3.Node{
+								{
+									Type: "floating_con",
+									Nodes: []*i3.Node{
+										{
+											Type:             "con",
+											Name:             "Mozilla Firefox",
+											WindowProperties: i3.WindowProperties{Class: "Firefox"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Name: "DP-2",
+					Type: "output",
+					Nodes: []*i3.Node{
+						{
+							Name: "2",
+							Type: "workspace",
+							Nodes: []*i3.Node{
+								{
+									Type:             "con",
+									Name:             "#general",
+									Urgent:           true,
+									WindowProperties: i3.WindowProperties{Class: "Slack"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// names lists the windows left in the tree
+func names(node *i3.Node) []string {
+	if node == nil {
+		return nil
+	}
+
+	var found []string
+	if node.Type == "con" && len(node.Nodes) == 0 {
+		found = append(found, node.Name)
+	}
+	for _, n := range node.Nodes {
+		found = append(found, names(n)...)
+	}
+	for _, n := range node.FloatingNodes {
+		found = append(found, names(n)...)
+	}
+	return found
+}
+
+func TestFilter(t *testing.T) {
+	cases := []struct {
+		expr string
+		want []string
+	}{
+		{"class=Firefox", []string{"Jira - Mozilla Firefox", "Mozilla Firefox"}},
+		{"class=firefox", nil},
+		{"class!=Firefox", []string{"vim", "#general"}},
+		{"not floating", []string{"Jira - Mozilla Firefox", "vim", "#general"}},
+		{"not urgent", []string{"Jira - Mozilla Firefox", "vim", "Mozilla Firefox"}},
+		{"layout!=tabbed", []string{"Jira - Mozilla Firefox", "Mozilla Firefox", "#general"}},
+		{"title~/jira/i", []string{"Jira - Mozilla Firefox"}},
+		{"title~Jira", []string{"Jira - Mozilla Firefox"}},
+		{"type=con and title!~/firefox/i and title~.", []string{"vim", "#general"}},
+		{`title="Mozilla Firefox"`, []string{"Mozilla Firefox"}},
+		{"class=Firefox and not floating", []string{"Jira - Mozilla Firefox"}},
+		{"floating=true and type=con", []string{"Mozilla Firefox"}},
+		{"class=Firefox or title~/jira/i and not floating", []string{"Jira - Mozilla Firefox", "Mozilla Firefox"}},
+		{"(class=Firefox or title~/jira/i) and not floating", []string{"Jira - Mozilla Firefox"}},
+		{"mark=edit", []string{"vim"}},
+		{"instance=term", []string{"vim"}},
+		{"urgent", []string{"#general"}},
+		{"focused", []string{"vim"}},
+		{"fullscreen", nil},
+		{"output=DP-2", []string{"#general"}},
+		{"type=con and workspace=1 and floating!=true", []string{"Jira - Mozilla Firefox", "vim"}},
+		{"layout=tabbed", []string{"vim"}},
+		{"type=con and layout=tabbed", []string{"vim"}},
+		{"type=workspace AND NOT workspace=2", []string{"Jira - Mozilla Firefox", "vim", "Mozilla Firefox"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := prune.NewFilter(tt.expr)
+			assert.NilError(t, err)
+
+			got := f.Prune(filterTree())
+
+			assert.DeepEqual(t, tt.want, names(got.Root))
+		})
+	}
+}
+
+func TestFilterKeepsAncestors(t *testing.T) {
+	f, err := prune.NewFilter("class=Alacritty")
+	assert.NilError(t, err)
+
+	got := f.Prune(filterTree())
+
+	want := &i3.Tree{
+		Root: &i3.Node{
+			Type: "root",
+			Nodes: []*i3.Node{
+				{
+					Name: "DP-1",
+					Type: "output",
+					Nodes: []*i3.Node{
+						{
+							Name: "1",
+							Type: "workspace",
+							Nodes: []*i3.Node{
+								{
+									Type:   "con",
+									Layout: "tabbed",
+									Nodes: []*i3.Node{
+										{
+											Type:             "con",
+											Name:             "vim",
+											Focused:          true,
+											Marks:            []string{"edit"},
+											WindowProperties: i3.WindowProperties{Class: "Alacritty", Instance: "term"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	assert.DeepEqual(t, want, got)
+}
+
+func TestFilterNoMatch(t *testing.T) {
+	f, err := prune.NewFilter("class=Nope")
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, &i3.Tree{}, f.Prune(filterTree()))
+	assert.DeepEqual(t, &i3.Tree{}, f.Prune(&i3.Tree{}))
+}
+
+func TestFilterErrors(t *testing.T) {
+	cases := []struct {
+		expr   string
+		column int
+	}{
+		{"", 1},
+		{"clas=Firefox", 1},
+		{"class=Firefox or", 17},
+		{"class=Firefox nope", 15},
+		{"class Firefox", 7},
+		{"class=", 7},
+		{"title~/jira", 7},
+		{"title~/jira/x", 13},
+		{"title~/(/", 7},
+		{`title="jira`, 7},
+		{"(class=Firefox or urgent", 1},
+		{"urgent~x", 7},
+		{"urgent=maybe", 8},
+		{"class=Firefox and )", 19},
+		{"title=ä and nope", 13},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := prune.NewFilter(tt.expr)
+
+			ferr, ok := err.(prune.FilterError)
+			assert.Assert(t, ok, "got %v", err)
+			assert.Equal(t, tt.column, ferr.Column, ferr.Error())
+		})
+	}
+}
+
+func TestFilterErrorMessage(t *testing.T) {
+	_, err := prune.NewFilter("class=Firefox or clas=x")
+
+	assert.Error(t, err, "invalid filter at column 18: unknown field \"clas\"\n"+
+		"  class=Firefox or clas=x\n"+
+		"                   ^")
+}
+
+func TestFilterErrorMessageRunes(t *testing.T) {
+	cases := []struct {
+		expr string
+		msg  string
+	}{
+		{"urgent or → x", `expected a field, found "→"`},
+		{"title~/x/é", `unknown regular expression flag "é"`},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := prune.NewFilter(tt.expr)
+
+			ferr, ok := err.(prune.FilterError)
+			assert.Assert(t, ok, "got %v", err)
+			assert.Equal(t, tt.msg, ferr.Msg)
+		})
+	}
+}
+
+func TestIsFilter(t *testing.T) {
+	cases := []struct {
+		arg  string
+		want bool
+	}{
+		{"class=Firefox", true},
+		{"not floating", true},
+		{"(urgent)", true},
+		{" Title~x", true},
+		{"layout!=tabbed", true},
+		{"urgent", false},
+		{"focused", false},
+		{"output 2", false},
+		{"title: x", false},
+		{"notes", false},
+		{"5", false},
+		{"3:mail", false},
+		{"mail", false},
+		{"\U000f0293 1", false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.arg, func(t *testing.T) {
+			assert.Equal(t, tt.want, prune.IsFilter(tt.arg))
+		})
+	}
+}
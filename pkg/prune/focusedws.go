@@ -12,8 +12,9 @@ func (w *FocusedWs) Prune(tree *i3.Tree) *i3.Tree {
 		i, _ := w.walk(tree.Root)
 
 		if i != "" {
-			a := Ws{WsIndex: i}
-			return a.Prune(tree)
+			return pruneSubtree(tree, func(src *i3.Node) bool {
+				return src.Type == "workspace" && src.Name == i
+			})
 		}
 	}
 
@@ -0,0 +1,87 @@
+package prune
+
+import "go.i3wm.org/i3/v4"
+
+// Match keeps the nodes matching a filter with the ones around them,
+// the way grep -C keeps lines: every matching node is kept whole,
+// along with its ancestors and up to Context siblings on each side of it
+// Tiling and floating siblings are counted apart
+type Match struct {
+	Filter  *Filter
+	Context int
+}
+
+// NewMatch parses the filter expression of a Match, failing with a FilterError
+func NewMatch(expr string, context int) (*Match, error) {
+	f, err := NewFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Match{Filter: f, Context: context}, nil
+}
+
+func (m *Match) Prune(tree *i3.Tree) *i3.Tree {
+	if tree.Root == nil {
+		return &i3.Tree{}
+	}
+
+	matching := m.Filter.Matching(tree)
+
+	var helper func(node *i3.Node) *i3.Node
+	helper = func(node *i3.Node) *i3.Node {
+		if matching[node] {
+			return copyNode(node)
+		}
+
+		nodes := m.keep(node.Nodes, matching, helper)
+		floating := m.keep(node.FloatingNodes, matching, helper)
+		if len(nodes) > 0 || len(floating) > 0 {
+			return withChildren(node, nodes, floating)
+		}
+
+		return nil
+	}
+
+	return &i3.Tree{
+		Root: helper(tree.Root),
+	}
+}
+
+// Matching returns the nodes of tree matching the filter
+// so they can be told apart from their context
+func (m *Match) Matching(tree *i3.Tree) map[*i3.Node]bool {
+	return m.Filter.Matching(tree)
+}
+
+// keep returns the siblings holding a match, pruned by helper,
+// and whole copies of the ones within Context of a matching one
+func (m *Match) keep(siblings []*i3.Node, matching map[*i3.Node]bool, helper func(*i3.Node) *i3.Node) []*i3.Node {
+	kept := make([]*i3.Node, len(siblings))
+	inContext := make([]bool, len(siblings))
+
+	for i, n := range siblings {
+		kept[i] = helper(n)
+		if !matching[n] {
+			continue
+		}
+
+		for j := i - m.Context; j <= i+m.Context; j++ {
+			if j >= 0 && j < len(siblings) {
+				inContext[j] = true
+			}
+		}
+	}
+
+	var nodes []*i3.Node
+	for i, n := range siblings {
+		switch {
+		case kept[i] != nil:
+			nodes = append(nodes, kept[i])
+		case inContext[i]:
+			nodes = append(nodes, copyNode(n))
+		}
+	}
+
+	return nodes
+}
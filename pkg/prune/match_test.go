@@ -0,0 +1,103 @@
+package prune_test
+
+import (
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/njhoffman/i3-tree/pkg/prune"
+	"go.i3wm.org/i3/v4"
+	"gotest.tools/assert"
+)
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		expr    string
+		context int
+		want    []string
+	}{
+		{"title=Slack", 0, []string{"Slack"}},
+		{"title=Slack", 1, []string{"VLC media player", "Slack"}},
+		{"title=Slack", 5, []string{"kubernetes.io - Mozilla Firefox", "VLC media player", "Slack"}},
+		{`title="Twitter.com - Mozilla Firefox"`, 1, []string{"Twitter.com - Mozilla Firefox", "Stackoverflow.com - Google Chrome"}},
+		// VLC is alone in its split, the split next to it isn't a sibling
+		{`title="VLC media player" and workspace=6`, 1, []string{"VLC media player"}},
+		{"title~/firefox/i", 0, []string{
+			"Reddit.com - Mozilla Firefox",
+			"Twitter.com - Mozilla Firefox",
+			"Mozilla Firefox",
+			"kubernetes.io - Mozilla Firefox",
+		}},
+		{"title=nothing", 1, nil},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.expr, func(t *testing.T) {
+			tree, err := fetch.FromFake{}.Fetch()
+			assert.NilError(t, err)
+
+			m, err := prune.NewMatch(tt.expr, tt.context)
+			assert.NilError(t, err)
+
+			assert.DeepEqual(t, tt.want, names(m.Prune(&tree).Root))
+		})
+	}
+}
+
+func TestMatchKeepsMatchesWhole(t *testing.T) {
+	m, err := prune.NewMatch("workspace=1 and type=workspace", 0)
+	assert.NilError(t, err)
+
+	got := m.Prune(filterTree())
+
+	assert.DeepEqual(t, []string{"Jira - Mozilla Firefox", "vim", "Mozilla Firefox"}, names(got.Root))
+	assert.Equal(t, 1, len(got.Root.Nodes))
+}
+
+func TestMatchContextDoesNotWidenAncestors(t *testing.T) {
+	m, err := prune.NewMatch("class=Slack", 3)
+	assert.NilError(t, err)
+
+	got := m.Prune(filterTree())
+
+	// the output of the match is kept, not the one next to it
+	assert.Equal(t, 1, len(got.Root.Nodes))
+	assert.Equal(t, "DP-2", got.Root.Nodes[0].Name)
+}
+
+func TestMatchNegation(t *testing.T) {
+	m, err := prune.NewMatch("not urgent", 0)
+	assert.NilError(t, err)
+
+	tree := filterTree()
+	got := m.Prune(tree)
+
+	assert.DeepEqual(t, []string{"Jira - Mozilla Firefox", "vim", "Mozilla Firefox"}, names(got.Root))
+	assert.Equal(t, 1, len(got.Root.Nodes))
+	assert.Equal(t, 3, len(m.Matching(tree)))
+}
+
+func TestMatchEmptyTree(t *testing.T) {
+	m, err := prune.NewMatch("urgent", 1)
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, &i3.Tree{}, m.Prune(&i3.Tree{}))
+}
+
+func TestMatchBadExpression(t *testing.T) {
+	_, err := prune.NewMatch("class=", 1)
+
+	assert.ErrorType(t, err, prune.FilterError{})
+}
+
+func TestMatching(t *testing.T) {
+	tree := filterTree()
+	m, err := prune.NewMatch("class=Firefox or urgent", 0)
+	assert.NilError(t, err)
+
+	got := m.Matching(tree)
+
+	assert.Equal(t, 3, len(got))
+	assert.Assert(t, got[tree.Root.Nodes[0].Nodes[0].Nodes[0]])
+	assert.Assert(t, got[tree.Root.Nodes[0].Nodes[0].FloatingNodes[0].Nodes[0]])
+	assert.Assert(t, got[tree.Root.Nodes[1].Nodes[0].Nodes[0]])
+}
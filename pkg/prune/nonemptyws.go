@@ -3,15 +3,23 @@ package prune
 import "go.i3wm.org/i3/v4"
 
 // Non Empty Workspace Pruner
-type NonEmptyWs struct{}
+// i3's internal scratchpad workspace is left out unless IncludeScratch is set
+type NonEmptyWs struct {
+	IncludeScratch bool
+}
 
 func (w *NonEmptyWs) Prune(tree *i3.Tree) *i3.Tree {
 	return pruneSubtree(tree, func(src *i3.Node) bool {
-		// there's a workspace
-		// and there are things within it
-		if src != nil && src.Type == "workspace" && len(src.Nodes) > 0 {
-			return true
+		if src == nil || src.Type != "workspace" {
+			return false
+		}
+
+		// the scratchpad only ever holds floating windows
+		if src.Name == ScratchWorkspace {
+			return w.IncludeScratch && len(src.FloatingNodes) > 0
 		}
-		return false
+
+		// there are things within the workspace
+		return len(src.Nodes) > 0
 	})
 }
@@ -2,8 +2,9 @@ package prune
 
 import "go.i3wm.org/i3/v4"
 
+// NoOp keeps the whole tree
 type NoOp struct{}
 
 func (w *NoOp) Prune(tree *i3.Tree) *i3.Tree {
-	return tree
+	return Copy(tree)
 }
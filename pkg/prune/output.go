@@ -0,0 +1,151 @@
+package prune
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"go.i3wm.org/i3/v4"
+)
+
+// Output names with a special meaning
+const (
+	// the output of the focused window
+	FocusedOutput = "focused"
+	// the primary output, as told by an OutputLister
+	PrimaryOutput = "primary"
+)
+
+// OutputLister lists the outputs of the window manager
+// they tell what the tree doesn't, such as which one is primary
+type OutputLister interface {
+	Outputs() ([]i3.Output, error)
+}
+
+// Output keeps the outputs whose name matches Pattern
+// a glob such as DP-*, FocusedOutput or PrimaryOutput
+// Nothing is kept when the output can't be found, e.g. PrimaryOutput without Lister
+type Output struct {
+	Pattern string
+	Lister  OutputLister
+}
+
+// NewOutput creates an Output pruner, failing when pattern isn't a valid glob
+func NewOutput(pattern string, lister OutputLister) (*Output, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	return &Output{Pattern: pattern, Lister: lister}, nil
+}
+
+func (o *Output) Prune(tree *i3.Tree) *i3.Tree {
+	t, _ := o.TryPrune(tree)
+	return t
+}
+
+// TryPrune fails with a NoOutputError when no output is kept,
+// and with the error of Lister when it can't list the outputs
+func (o *Output) TryPrune(tree *i3.Tree) (*i3.Tree, error) {
+	if tree.Root == nil {
+		return &i3.Tree{}, nil
+	}
+
+	match, err := o.matcher(tree.Root)
+	if err != nil {
+		return &i3.Tree{}, err
+	}
+
+	found := false
+	for _, n := range tree.Root.Nodes {
+		found = found || n.Type == "output" && match(n.Name)
+	}
+	if !found {
+		return &i3.Tree{}, NoOutputError{Selector: o.Pattern, Available: outputNames(tree.Root)}
+	}
+
+	return pruneSubtree(tree, func(src *i3.Node) bool {
+		return src.Type == "output" && match(src.Name)
+	}), nil
+}
+
+// NoOutputError tells a pattern matched none of the available outputs
+type NoOutputError struct {
+	Selector  string
+	Available []string
+}
+
+func (e NoOutputError) Error() string {
+	if len(e.Available) == 0 {
+		return fmt.Sprintf("no output matches %q, there are no outputs", e.Selector)
+	}
+	return fmt.Sprintf("no output matches %q, available: %s", e.Selector, strings.Join(e.Available, ", "))
+}
+
+// matcher resolves the special output names
+// a name that can't be resolved matches nothing
+func (o *Output) matcher(root *i3.Node) (func(string) bool, error) {
+	var name string
+
+	switch o.Pattern {
+	case FocusedOutput:
+		name = focusedOutput(root)
+
+	case PrimaryOutput:
+		if o.Lister == nil {
+			break
+		}
+		outputs, err := o.Lister.Outputs()
+		if err != nil {
+			return nil, fmt.Errorf("can't list the outputs: %w", err)
+		}
+		for _, out := range outputs {
+			if out.Primary {
+				name = out.Name
+			}
+		}
+
+	default:
+		return func(s string) bool {
+			ok, _ := path.Match(o.Pattern, s)
+			return ok
+		}, nil
+	}
+
+	return func(s string) bool {
+		return name != "" && s == name
+	}, nil
+}
+
+// outputNames lists the outputs of the tree, but i3's internal one
+func outputNames(root *i3.Node) []string {
+	var names []string
+	for _, n := range root.Nodes {
+		if n.Type == "output" && n.Name != "__i3" {
+			names = append(names, n.Name)
+		}
+	}
+	return names
+}
+
+// focusedOutput returns the name of the output with the focused window
+func focusedOutput(node *i3.Node) string {
+	var walk func(node *i3.Node, output string) string
+	walk = func(node *i3.Node, output string) string {
+		if node.Type == "output" {
+			output = node.Name
+		}
+		if node.Focused {
+			return output
+		}
+
+		for _, n := range append(append([]*i3.Node{}, node.Nodes...), node.FloatingNodes...) {
+			if found := walk(n, output); found != "" {
+				return found
+			}
+		}
+		return ""
+	}
+
+	return walk(node, "")
+}
@@ -0,0 +1,156 @@
+package prune_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/prune"
+	"go.i3wm.org/i3/v4"
+	"gotest.tools/assert"
+)
+
+// outputLister lists fixed outputs
+type outputLister struct {
+	outputs []i3.Output
+	err     error
+}
+
+func (l outputLister) Outputs() ([]i3.Output, error) {
+	return l.outputs, l.err
+}
+
+// outputNames lists the outputs left in the tree
+func outputNames(tree *i3.Tree) []string {
+	if tree.Root == nil {
+		return nil
+	}
+
+	var found []string
+	for _, n := range tree.Root.Nodes {
+		if n.Type == "output" {
+			found = append(found, n.Name)
+		}
+	}
+	return found
+}
+
+func TestOutput(t *testing.T) {
+	lister := outputLister{outputs: []i3.Output{
+		{Name: "DP-1", Active: true},
+		{Name: "DP-2", Active: true, Primary: true},
+	}}
+
+	cases := []struct {
+		name    string
+		pattern string
+		lister  prune.OutputLister
+		want    []string
+	}{
+		{"name", "DP-2", nil, []string{"DP-2"}},
+		{"glob", "DP-*", nil, []string{"DP-1", "DP-2"}},
+		{"glob single character", "DP-?", nil, []string{"DP-1", "DP-2"}},
+		{"no match", "HDMI-*", nil, nil},
+		{"focused", "focused", nil, []string{"DP-1"}},
+		{"primary", "primary", lister, []string{"DP-2"}},
+		{"primary without lister", "primary", nil, nil},
+		{"primary lister error", "primary", outputLister{err: errors.New("no i3")}, nil},
+		{"no primary", "primary", outputLister{outputs: []i3.Output{{Name: "DP-1"}}}, nil},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := prune.NewOutput(tt.pattern, tt.lister)
+			assert.NilError(t, err)
+
+			got := o.Prune(filterTree())
+
+			assert.DeepEqual(t, tt.want, outputNames(got))
+		})
+	}
+}
+
+func TestOutputTryPrune(t *testing.T) {
+	listErr := errors.New("no i3")
+
+	cases := []struct {
+		name    string
+		pattern string
+		lister  prune.OutputLister
+		wantErr error
+	}{
+		{"no match", "HDMI-*", nil, prune.NoOutputError{Selector: "HDMI-*", Available: []string{"DP-1", "DP-2"}}},
+		{"primary without lister", "primary", nil, prune.NoOutputError{Selector: "primary", Available: []string{"DP-1", "DP-2"}}},
+		{
+			"no primary",
+			"primary",
+			outputLister{outputs: []i3.Output{{Name: "DP-1"}}},
+			prune.NoOutputError{Selector: "primary", Available: []string{"DP-1", "DP-2"}},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := prune.NewOutput(tt.pattern, tt.lister)
+			assert.NilError(t, err)
+
+			got, err := o.TryPrune(filterTree())
+
+			assert.DeepEqual(t, &i3.Tree{}, got)
+			assert.DeepEqual(t, tt.wantErr, err)
+		})
+	}
+
+	t.Run("lister error", func(t *testing.T) {
+		o, err := prune.NewOutput("primary", outputLister{err: listErr})
+		assert.NilError(t, err)
+
+		_, err = o.TryPrune(filterTree())
+
+		assert.Assert(t, errors.Is(err, listErr))
+		assert.ErrorContains(t, err, "can't list the outputs")
+	})
+
+	t.Run("nothing focused", func(t *testing.T) {
+		o, err := prune.NewOutput("focused", nil)
+		assert.NilError(t, err)
+
+		_, err = o.TryPrune(&i3.Tree{Root: &i3.Node{
+			Type:  "root",
+			Nodes: []*i3.Node{{Type: "output", Name: "DP-1"}},
+		}})
+
+		assert.DeepEqual(t, prune.NoOutputError{Selector: "focused", Available: []string{"DP-1"}}, err)
+		assert.Error(t, err, `no output matches "focused", available: DP-1`)
+	})
+
+	t.Run("no outputs", func(t *testing.T) {
+		o, err := prune.NewOutput("DP-1", nil)
+		assert.NilError(t, err)
+
+		_, err = o.TryPrune(&i3.Tree{Root: &i3.Node{Type: "root"}})
+
+		assert.Error(t, err, `no output matches "DP-1", there are no outputs`)
+	})
+}
+
+func TestOutputKeepsWholeOutput(t *testing.T) {
+	o, err := prune.NewOutput("DP-2", nil)
+	assert.NilError(t, err)
+
+	got := o.Prune(filterTree())
+
+	assert.DeepEqual(t, []string{"#general"}, names(got.Root))
+}
+
+func TestOutputEmptyTree(t *testing.T) {
+	o, err := prune.NewOutput("focused", nil)
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, &i3.Tree{}, o.Prune(&i3.Tree{}))
+}
+
+func TestNewOutputBadPattern(t *testing.T) {
+	_, err := prune.NewOutput("DP-[", nil)
+
+	assert.ErrorContains(t, err, "syntax error")
+}
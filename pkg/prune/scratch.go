@@ -0,0 +1,29 @@
+package prune
+
+import (
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"go.i3wm.org/i3/v4"
+)
+
+// Names of i3's internal containers holding the scratchpad
+const (
+	ScratchOutput    = "__i3"
+	ScratchWorkspace = "__i3_scratch"
+)
+
+// Scratch keeps the windows sent to the scratchpad
+// i3 keeps the hidden ones as floating windows of its internal __i3_scratch workspace
+// Windows shown from the scratchpad are on regular workspaces,
+// they are kept when Extras tell their scratchpad_state
+type Scratch struct {
+	Extras fetch.Extras
+}
+
+func (s *Scratch) Prune(tree *i3.Tree) *i3.Tree {
+	return pruneSubtree(tree, func(src *i3.Node) bool {
+		if src.Type == "workspace" && src.Name == ScratchWorkspace {
+			return len(src.Nodes)+len(src.FloatingNodes) > 0
+		}
+		return s.Extras[src.ID].InScratchpad()
+	})
+}
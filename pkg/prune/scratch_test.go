@@ -0,0 +1,111 @@
+package prune_test
+
+import (
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/njhoffman/i3-tree/pkg/prune"
+	"go.i3wm.org/i3/v4"
+	"gotest.tools/assert"
+)
+
+func scratchTree(scratchWindows ...string) *i3.Tree {
+	scratch := &i3.Node{Name: "__i3_scratch", Type: "workspace"}
+	for _, name := range scratchWindows {
+		scratch.FloatingNodes = append(scratch.FloatingNodes, &i3.Node{
+			Type:  "floating_con",
+			Nodes: []*i3.Node{{Type: "con", Name: name}},
+		})
+	}
+
+	return &i3.Tree{
+		Root: &i3.Node{
+			Type: "root",
+			Nodes: []*i3.Node{
+				{
+					Name: "__i3",
+					Type: "output",
+					Nodes: []*i3.Node{
+						{Type: "con", Name: "content", Nodes: []*i3.Node{scratch}},
+					},
+				},
+				{
+					Name: "DP-1",
+					Type: "output",
+					Nodes: []*i3.Node{
+						{
+							Name:  "1",
+							Type:  "workspace",
+							Nodes: []*i3.Node{{Type: "con", Name: "vim"}},
+						},
+						{
+							Name: "2",
+							Type: "workspace",
+							FloatingNodes: []*i3.Node{
+								{ID: 7, Type: "floating_con", Nodes: []*i3.Node{{Type: "con", Name: "mpv"}}},
+							},
+						},
+						{Name: "3", Type: "workspace"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestScratch(t *testing.T) {
+	t.Run("empty tree", func(t *testing.T) {
+		s := &prune.Scratch{}
+
+		assert.DeepEqual(t, &i3.Tree{}, s.Prune(&i3.Tree{}))
+	})
+
+	t.Run("empty scratchpad", func(t *testing.T) {
+		s := &prune.Scratch{}
+
+		assert.DeepEqual(t, &i3.Tree{}, s.Prune(scratchTree()))
+	})
+
+	t.Run("scratchpad windows", func(t *testing.T) {
+		s := &prune.Scratch{}
+		got := s.Prune(scratchTree("KeePassXC", "htop"))
+
+		assert.DeepEqual(t, []string{"__i3"}, outputNames(got))
+		assert.DeepEqual(t, []string{"KeePassXC", "htop"}, names(got.Root))
+	})
+
+	t.Run("windows shown from the scratchpad", func(t *testing.T) {
+		s := &prune.Scratch{Extras: fetch.Extras{
+			7: {ScratchpadState: "changed"},
+		}}
+		got := s.Prune(scratchTree("KeePassXC"))
+
+		assert.DeepEqual(t, []string{"__i3", "DP-1"}, outputNames(got))
+		assert.DeepEqual(t, []string{"KeePassXC", "mpv"}, names(got.Root))
+	})
+}
+
+func TestNonEmptyWorkspaceScratch(t *testing.T) {
+	t.Run("scratchpad hidden by default", func(t *testing.T) {
+		w := &prune.NonEmptyWs{}
+		got := w.Prune(scratchTree("KeePassXC"))
+
+		assert.DeepEqual(t, []string{"DP-1"}, outputNames(got))
+		assert.DeepEqual(t, []string{"1"}, workspaceNames(got.Root))
+	})
+
+	t.Run("scratchpad included", func(t *testing.T) {
+		w := &prune.NonEmptyWs{IncludeScratch: true}
+		got := w.Prune(scratchTree("KeePassXC"))
+
+		assert.DeepEqual(t, []string{"__i3", "DP-1"}, outputNames(got))
+		assert.DeepEqual(t, []string{"__i3_scratch", "1"}, workspaceNames(got.Root))
+	})
+
+	t.Run("empty scratchpad included", func(t *testing.T) {
+		w := &prune.NonEmptyWs{IncludeScratch: true}
+		got := w.Prune(scratchTree())
+
+		assert.DeepEqual(t, []string{"DP-1"}, outputNames(got))
+	})
+}
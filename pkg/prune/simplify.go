@@ -0,0 +1,86 @@
+package prune
+
+import "go.i3wm.org/i3/v4"
+
+// Simplify removes the containers i3 nests splits with
+// that only add noise to the tree, e.g.
+//
+//	[con][splith] -> [con][splitv] -> window
+//
+// becomes the window alone
+//
+//   - a split container with a single child is replaced by that child,
+//     whose layout is the one that matters
+//   - a workspace with a single split container takes its children and layout
+//
+// Containers with anything worth showing (name, window, marks, focus,
+// urgency, fullscreen, floating children) and tabbed or stacked ones,
+// which draw a title bar even for a single child, are kept
+type Simplify struct{}
+
+func (s *Simplify) Prune(tree *i3.Tree) *i3.Tree {
+	if tree.Root == nil {
+		return &i3.Tree{}
+	}
+
+	return &i3.Tree{
+		Root: s.simplify(tree.Root),
+	}
+}
+
+// simplify copies node, collapsing the redundant containers within it
+func (s *Simplify) simplify(node *i3.Node) *i3.Node {
+	c := withChildren(node, nil, nil)
+	if node.Nodes != nil {
+		c.Nodes = make([]*i3.Node, len(node.Nodes))
+		for i, n := range node.Nodes {
+			c.Nodes[i] = s.simplify(n)
+		}
+	}
+	if node.FloatingNodes != nil {
+		c.FloatingNodes = make([]*i3.Node, len(node.FloatingNodes))
+		for i, n := range node.FloatingNodes {
+			c.FloatingNodes[i] = s.simplify(n)
+		}
+	}
+
+	// children were simplified first, so chains collapse all the way down
+	for i, n := range c.Nodes {
+		if isRedundant(n) && len(n.Nodes) == 1 {
+			c.Nodes[i] = n.Nodes[0]
+			replaceFocus(c, n.ID, n.Nodes[0].ID)
+		}
+	}
+
+	if c.Type == "workspace" && len(c.Nodes) == 1 && isRedundant(c.Nodes[0]) {
+		only := c.Nodes[0]
+		c.Layout = only.Layout
+		c.Nodes = only.Nodes
+		c.Focus = only.Focus
+	}
+
+	return c
+}
+
+// isRedundant tells if a container only exists to split the space of its children
+func isRedundant(n *i3.Node) bool {
+	return n.Type == "con" &&
+		(n.Layout == "splith" || n.Layout == "splitv") &&
+		len(n.Nodes) > 0 &&
+		len(n.FloatingNodes) == 0 &&
+		n.Name == "" &&
+		n.Window == 0 &&
+		len(n.Marks) == 0 &&
+		!n.Focused &&
+		!n.Urgent &&
+		n.FullscreenMode == 0
+}
+
+// replaceFocus updates the focus order of node after one of its children was replaced
+func replaceFocus(node *i3.Node, old i3.NodeID, new i3.NodeID) {
+	for i, id := range node.Focus {
+		if id == old {
+			node.Focus[i] = new
+		}
+	}
+}
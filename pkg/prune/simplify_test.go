@@ -0,0 +1,112 @@
+package prune_test
+
+import (
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/njhoffman/i3-tree/pkg/prune"
+	"go.i3wm.org/i3/v4"
+	"gotest.tools/assert"
+)
+
+func TestSimplify(t *testing.T) {
+	s := &prune.Simplify{}
+
+	t.Run("empty tree", func(t *testing.T) {
+		assert.DeepEqual(t, &i3.Tree{}, s.Prune(&i3.Tree{}))
+	})
+
+	t.Run("mock", func(t *testing.T) {
+		tree, err := fetch.FromFake{}.Fetch()
+		assert.NilError(t, err)
+
+		got := s.Prune(&tree)
+		hdmi0, hdmi1 := got.Root.Nodes[0], got.Root.Nodes[1]
+
+		// workspaces holding windows directly are left alone
+		want, _ := fetch.FromFake{}.Fetch()
+		for i := 0; i < 4; i++ {
+			assert.DeepEqual(t, want.Root.Nodes[0].Nodes[i], hdmi0.Nodes[i])
+		}
+
+		// splits holding more than one window are kept
+		ws5 := hdmi0.Nodes[4]
+		assert.DeepEqual(t, want.Root.Nodes[0].Nodes[4], ws5)
+
+		// the split holding VLC alone is replaced by it
+		ws6 := hdmi1.Nodes[0]
+		assert.Equal(t, "splith", string(ws6.Layout))
+		assert.Equal(t, 2, len(ws6.Nodes))
+		assert.Equal(t, "VLC media player", ws6.Nodes[0].Name)
+		assert.Assert(t, ws6.Nodes[0].Focused)
+		assert.Equal(t, "splitv", string(ws6.Nodes[1].Layout))
+		assert.Equal(t, 2, len(ws6.Nodes[1].Nodes))
+	})
+
+	t.Run("chain of splits", func(t *testing.T) {
+		window := &i3.Node{ID: 4, Name: "vim", Type: "con", Window: 42}
+		ws := &i3.Node{ID: 1, Name: "1", Type: "workspace", Layout: "splith", Focus: []i3.NodeID{2, 5}, Nodes: []*i3.Node{
+			{ID: 2, Type: "con", Layout: "splith", Focus: []i3.NodeID{3}, Nodes: []*i3.Node{
+				{ID: 3, Type: "con", Layout: "splitv", Focus: []i3.NodeID{4}, Nodes: []*i3.Node{window}},
+			}},
+			{ID: 5, Name: "htop", Type: "con", Window: 43},
+		}}
+
+		got := s.Prune(&i3.Tree{Root: ws})
+
+		assert.DeepEqual(t, []*i3.Node{window, ws.Nodes[1]}, got.Root.Nodes)
+		assert.DeepEqual(t, []i3.NodeID{4, 5}, got.Root.Focus)
+	})
+
+	t.Run("workspace takes the layout of its only split", func(t *testing.T) {
+		ws := &i3.Node{ID: 1, Name: "1", Type: "workspace", Layout: "splith", Focus: []i3.NodeID{2}, Nodes: []*i3.Node{
+			{ID: 2, Type: "con", Layout: "splitv", Focus: []i3.NodeID{4, 3}, Nodes: []*i3.Node{
+				{ID: 3, Name: "a", Type: "con", Window: 42},
+				{ID: 4, Name: "b", Type: "con", Window: 43},
+			}},
+		}}
+
+		got := s.Prune(&i3.Tree{Root: ws})
+
+		assert.Equal(t, "1", got.Root.Name)
+		assert.Equal(t, "splitv", string(got.Root.Layout))
+		assert.DeepEqual(t, []string{"a", "b"}, []string{got.Root.Nodes[0].Name, got.Root.Nodes[1].Name})
+		assert.DeepEqual(t, []i3.NodeID{4, 3}, got.Root.Focus)
+	})
+
+	t.Run("containers worth showing are kept", func(t *testing.T) {
+		single := func(n *i3.Node) *i3.Tree {
+			n.ID, n.Type = 2, "con"
+			if n.Layout == "" {
+				n.Layout = "splith"
+			}
+			n.Nodes = []*i3.Node{{ID: 3, Name: "vim", Type: "con", Window: 42}}
+			return &i3.Tree{Root: &i3.Node{ID: 1, Type: "workspace", Layout: "splith", Nodes: []*i3.Node{
+				n,
+				{ID: 4, Name: "htop", Type: "con", Window: 43},
+			}}}
+		}
+
+		cases := []struct {
+			name string
+			node *i3.Node
+		}{
+			{"tabbed", &i3.Node{Layout: "tabbed"}},
+			{"stacked", &i3.Node{Layout: "stacked"}},
+			{"named", &i3.Node{Name: "main"}},
+			{"marked", &i3.Node{Marks: []string{"editor"}}},
+			{"focused", &i3.Node{Focused: true}},
+			{"urgent", &i3.Node{Urgent: true}},
+			{"fullscreen", &i3.Node{FullscreenMode: 1}},
+			{"floating children", &i3.Node{FloatingNodes: []*i3.Node{{ID: 5, Type: "floating_con"}}}},
+		}
+
+		for _, tt := range cases {
+			t.Run(tt.name, func(t *testing.T) {
+				tree := single(tt.node)
+
+				assert.DeepEqual(t, tree, s.Prune(tree))
+			})
+		}
+	})
+}
@@ -10,6 +10,8 @@ type pruneCond func(*i3.Node) bool
 // * a valid subtree is found
 //   which the whole subtree is returned immediately
 // * a leaf is found
+// floating nodes are explored the same way as tiling ones
+// the returned tree is a copy, the one given is never modified
 func pruneSubtree(tree *i3.Tree, checkFn pruneCond) *i3.Tree {
 	// we will call it recursively
 	// therefore it needs to be declared first
@@ -17,10 +19,10 @@ func pruneSubtree(tree *i3.Tree, checkFn pruneCond) *i3.Tree {
 
 	helper = func(src *i3.Node) *i3.Node {
 		if checkFn(src) {
-			return src
+			return copyNode(src)
 		}
 
-		subtrees := make([]*i3.Node, 0)
+		var subtrees []*i3.Node
 		for _, n := range src.Nodes {
 			r := helper(n)
 			if r != nil {
@@ -28,9 +30,16 @@ func pruneSubtree(tree *i3.Tree, checkFn pruneCond) *i3.Tree {
 			}
 		}
 
-		if len(subtrees) > 0 {
-			src.Nodes = subtrees
-			return src
+		var floating []*i3.Node
+		for _, n := range src.FloatingNodes {
+			r := helper(n)
+			if r != nil {
+				floating = append(floating, r)
+			}
+		}
+
+		if len(subtrees) > 0 || len(floating) > 0 {
+			return withChildren(src, subtrees, floating)
 		}
 
 		return nil
@@ -0,0 +1,112 @@
+package prune
+
+import "go.i3wm.org/i3/v4"
+
+// Visible keeps the workspace shown on every output
+// which is the one its output focused last in the tree,
+// or the current workspace Lister tells when the tree has no focus ordering
+// i3's internal __i3 output is never shown
+type Visible struct {
+	Lister OutputLister
+}
+
+func (v *Visible) Prune(tree *i3.Tree) *i3.Tree {
+	if tree.Root == nil {
+		return &i3.Tree{}
+	}
+
+	var current map[string]string
+	visible := make(map[*i3.Node]bool)
+
+	var walk func(node *i3.Node)
+	walk = func(node *i3.Node) {
+		if node.Type != "output" {
+			for _, n := range node.Nodes {
+				walk(n)
+			}
+			return
+		}
+
+		if node.Name == ScratchOutput {
+			return
+		}
+
+		ws := shownWorkspace(node)
+		if ws == nil {
+			if current == nil {
+				current = v.currentWorkspaces()
+			}
+			ws = findWorkspace(node, current[node.Name])
+		}
+		if ws != nil {
+			visible[ws] = true
+		}
+	}
+	walk(tree.Root)
+
+	return pruneSubtree(tree, func(src *i3.Node) bool {
+		return visible[src]
+	})
+}
+
+// currentWorkspaces maps the outputs to the workspace they show
+func (v *Visible) currentWorkspaces() map[string]string {
+	current := make(map[string]string)
+	if v.Lister == nil {
+		return current
+	}
+
+	outputs, err := v.Lister.Outputs()
+	if err != nil {
+		return current
+	}
+	for _, o := range outputs {
+		if o.Active {
+			current[o.Name] = o.CurrentWorkspace
+		}
+	}
+
+	return current
+}
+
+// shownWorkspace finds the workspace focused last within node
+// i3 keeps workspaces in a content container of their output,
+// whose focus list starts with the one shown
+func shownWorkspace(node *i3.Node) *i3.Node {
+	for _, id := range node.Focus {
+		for _, n := range node.Nodes {
+			if n.ID == id && n.Type == "workspace" {
+				return n
+			}
+		}
+	}
+
+	for _, n := range node.Nodes {
+		if n.Type == "workspace" {
+			continue
+		}
+		if ws := shownWorkspace(n); ws != nil {
+			return ws
+		}
+	}
+
+	return nil
+}
+
+// findWorkspace finds a workspace by name within node
+func findWorkspace(node *i3.Node, name string) *i3.Node {
+	if name == "" {
+		return nil
+	}
+
+	for _, n := range node.Nodes {
+		if n.Type == "workspace" && n.Name == name {
+			return n
+		}
+		if ws := findWorkspace(n, name); ws != nil {
+			return ws
+		}
+	}
+
+	return nil
+}
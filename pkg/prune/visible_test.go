@@ -0,0 +1,111 @@
+package prune_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/njhoffman/i3-tree/pkg/prune"
+	"go.i3wm.org/i3/v4"
+	"gotest.tools/assert"
+)
+
+// visibleTree is laid out like i3's, with workspaces in content containers
+func visibleTree() *i3.Tree {
+	return &i3.Tree{
+		Root: &i3.Node{
+			ID:   1,
+			Type: "root",
+			Nodes: []*i3.Node{
+				{
+					ID:   2,
+					Name: "__i3",
+					Type: "output",
+					Nodes: []*i3.Node{
+						{ID: 3, Type: "con", Name: "content", Focus: []i3.NodeID{4}, Nodes: []*i3.Node{workspaceNode(4, "__i3_scratch")}},
+					},
+				},
+				{
+					ID:    5,
+					Name:  "DP-1",
+					Type:  "output",
+					Focus: []i3.NodeID{7, 6},
+					Nodes: []*i3.Node{
+						{ID: 6, Type: "dockarea", Name: "topdock"},
+						{
+							ID:    7,
+							Type:  "con",
+							Name:  "content",
+							Focus: []i3.NodeID{9, 8},
+							Nodes: []*i3.Node{workspaceNode(8, "1"), workspaceNode(9, "2")},
+						},
+					},
+				},
+				{
+					ID:   11,
+					Name: "DP-2",
+					Type: "output",
+					Nodes: []*i3.Node{
+						{
+							ID:    12,
+							Type:  "con",
+							Name:  "content",
+							Focus: []i3.NodeID{13, 14},
+							Nodes: []*i3.Node{workspaceNode(13, "3"), workspaceNode(14, "4")},
+						},
+					},
+				},
+				{
+					ID:   15,
+					Name: "HDMI-1",
+					Type: "output",
+					Nodes: []*i3.Node{
+						{ID: 16, Type: "con", Name: "content", Nodes: []*i3.Node{workspaceNode(17, "5"), workspaceNode(18, "6")}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestVisible(t *testing.T) {
+	t.Run("empty tree", func(t *testing.T) {
+		v := &prune.Visible{}
+
+		assert.DeepEqual(t, &i3.Tree{}, v.Prune(&i3.Tree{}))
+	})
+
+	t.Run("focus ordering", func(t *testing.T) {
+		v := &prune.Visible{}
+		got := v.Prune(visibleTree())
+
+		assert.DeepEqual(t, []string{"2", "3"}, workspaceNames(got.Root))
+	})
+
+	t.Run("current workspace of outputs without focus ordering", func(t *testing.T) {
+		v := &prune.Visible{Lister: outputLister{outputs: []i3.Output{
+			{Name: "DP-1", Active: true, CurrentWorkspace: "1"},
+			{Name: "HDMI-1", Active: true, CurrentWorkspace: "6"},
+		}}}
+		got := v.Prune(visibleTree())
+
+		assert.DeepEqual(t, []string{"2", "3", "6"}, workspaceNames(got.Root))
+	})
+
+	t.Run("lister failing", func(t *testing.T) {
+		v := &prune.Visible{Lister: outputLister{err: errors.New("no i3")}}
+		got := v.Prune(visibleTree())
+
+		assert.DeepEqual(t, []string{"2", "3"}, workspaceNames(got.Root))
+	})
+
+	t.Run("mock", func(t *testing.T) {
+		tree, err := fetch.FromFake{}.Fetch()
+		assert.NilError(t, err)
+
+		v := &prune.Visible{Lister: fetch.FromFake{}}
+		got := v.Prune(&tree)
+
+		assert.DeepEqual(t, []string{"1", "6"}, workspaceNames(got.Root))
+	})
+}
@@ -0,0 +1,22 @@
+package prune
+
+import "go.i3wm.org/i3/v4"
+
+// IsWindow tells leaf containers holding a window apart from empty placeholders
+func IsWindow(n *i3.Node) bool {
+	if n.Type != "con" || len(n.Nodes) > 0 || len(n.FloatingNodes) > 0 {
+		return false
+	}
+	return n.Window != 0 || n.Name != "" || n.WindowProperties.Class != ""
+}
+
+// WindowLabel names a window the way the console does, e.g. (firefox) Jira
+func WindowLabel(n *i3.Node) string {
+	if n.WindowProperties.Class == "" {
+		return n.Name
+	}
+	if n.Name == "" {
+		return "(" + n.WindowProperties.Class + ")"
+	}
+	return "(" + n.WindowProperties.Class + ") " + n.Name
+}
@@ -1,19 +1,211 @@
 package prune
 
-import "go.i3wm.org/i3/v4"
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
 
-// Ws prunes the tree maintaining a specific workspace
+	"go.i3wm.org/i3/v4"
+)
+
+// Ws prunes the tree maintaining the workspaces selected by WsIndex, a comma separated list of
+//   - names: mail
+//   - numbers, matching i3's num: 3 selects 3:mail
+//   - ranges of numbers: 1-4
+//   - globs on names: *mail*
+//   - regular expressions on names: /^\d:/ or /mail/i
+//
+// A workspace named exactly like an item is selected rather than what the item would match
 type Ws struct {
 	WsIndex string
 }
 
 func (w *Ws) Prune(tree *i3.Tree) *i3.Tree {
+	t, _ := w.TryPrune(tree)
+	return t
+}
+
+// TryPrune fails with a NoWorkspaceError when nothing is selected
+// and when a regular expression is invalid
+func (w *Ws) TryPrune(tree *i3.Tree) (*i3.Tree, error) {
+	names := workspaceNames(tree.Root, false)
+	all := workspaceNames(tree.Root, true)
+
+	selected := make(map[string]bool)
+	for _, item := range splitSelector(w.WsIndex) {
+		item = strings.TrimSpace(item)
+		if contains(all, item) {
+			selected[item] = true
+			continue
+		}
+
+		match, err := wsMatcher(item)
+		if err != nil {
+			return &i3.Tree{}, err
+		}
+
+		for _, name := range names {
+			if match(name) {
+				selected[name] = true
+			}
+		}
+	}
+
+	if len(selected) == 0 {
+		return &i3.Tree{}, NoWorkspaceError{Selector: w.WsIndex, Available: names}
+	}
+
 	return pruneSubtree(tree, func(src *i3.Node) bool {
-		// there's a workspace
-		// and there are things within it
-		if src != nil && src.Type == "workspace" && src.Name == w.WsIndex {
+		return src != nil && src.Type == "workspace" && selected[src.Name]
+	}), nil
+}
+
+// NoWorkspaceError tells a selector matched none of the available workspaces
+type NoWorkspaceError struct {
+	Selector  string
+	Available []string
+}
+
+func (e NoWorkspaceError) Error() string {
+	if len(e.Available) == 0 {
+		return fmt.Sprintf("no workspace matches %q, there are no workspaces", e.Selector)
+	}
+	return fmt.Sprintf("no workspace matches %q, available: %s", e.Selector, strings.Join(e.Available, ", "))
+}
+
+// splitSelector splits a workspace selector on the commas that aren't part of an item,
+// within a regular expression or a glob character class, e.g. /^\d{1,2}$/ or [1,3]*
+func splitSelector(selector string) []string {
+	var items []string
+	start, classDepth := 0, 0
+
+	for i, r := range selector {
+		item := selector[start:i]
+		switch {
+		case strings.HasPrefix(strings.TrimSpace(item), "/"):
+			if r == ',' && regexClosed(strings.TrimSpace(item)) {
+				items = append(items, item)
+				start = i + 1
+			}
+		case r == '[':
+			classDepth++
+		case r == ']' && classDepth > 0:
+			classDepth--
+		case r == ',' && classDepth == 0:
+			items = append(items, item)
+			start = i + 1
+		}
+	}
+
+	return append(items, selector[start:])
+}
+
+// regexClosed tells if item is a whole /regular expression/, optionally followed by i
+func regexClosed(item string) bool {
+	item = strings.TrimSuffix(item, "i")
+	return len(item) > 1 && strings.HasSuffix(item, "/") && !strings.HasSuffix(item, `\/`)
+}
+
+var wsRangeRe = regexp.MustCompile(`^(\d+)-(\d+)$`)
+
+// wsMatcher parses an item of a workspace selector that isn't a workspace name
+func wsMatcher(item string) (func(string) bool, error) {
+	if len(item) > 1 && strings.HasPrefix(item, "/") {
+		pattern := strings.TrimPrefix(item, "/")
+		switch {
+		case strings.HasSuffix(pattern, "/i"):
+			pattern = "(?i)" + strings.TrimSuffix(pattern, "/i")
+		case strings.HasSuffix(pattern, "/"):
+			pattern = strings.TrimSuffix(pattern, "/")
+		default:
+			return nil, fmt.Errorf("unclosed regular expression %q", item)
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString, nil
+	}
+
+	if strings.ContainsAny(item, "*?[") {
+		if _, err := path.Match(item, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %s", item, err)
+		}
+		return func(s string) bool {
+			ok, _ := path.Match(item, s)
+			return ok
+		}, nil
+	}
+
+	if m := wsRangeRe.FindStringSubmatch(item); m != nil {
+		from, _ := strconv.Atoi(m[1])
+		to, _ := strconv.Atoi(m[2])
+		return func(s string) bool {
+			num := WorkspaceNum(s)
+			return num >= from && num <= to
+		}, nil
+	}
+
+	if num, err := strconv.Atoi(item); err == nil && num >= 0 {
+		return func(s string) bool { return WorkspaceNum(s) == num }, nil
+	}
+
+	return func(s string) bool { return s == item }, nil
+}
+
+// WorkspaceNum is the number of a workspace, as i3 reads it from the digits its name starts with:
+// 3:mail is 3, mail or "<icon> 3" have none
+// It is -1 for workspaces without a number
+func WorkspaceNum(name string) int {
+	end := 0
+	for end < len(name) && name[end] >= '0' && name[end] <= '9' {
+		end++
+	}
+	if end == 0 {
+		return -1
+	}
+
+	num, err := strconv.Atoi(name[:end])
+	if err != nil {
+		return -1
+	}
+	return num
+}
+
+// workspaceNames lists the workspaces of the tree, in order
+// i3's internal __i3_scratch workspace is only listed with includeScratch,
+// as it's only selected by its exact name
+func workspaceNames(root *i3.Node, includeScratch bool) []string {
+	var names []string
+
+	var walk func(node *i3.Node)
+	walk = func(node *i3.Node) {
+		if node.Type == "workspace" {
+			if includeScratch || node.Name != ScratchWorkspace {
+				names = append(names, node.Name)
+			}
+			return
+		}
+		for _, n := range node.Nodes {
+			walk(n)
+		}
+	}
+
+	if root != nil {
+		walk(root)
+	}
+
+	return names
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
 			return true
 		}
-		return false
-	})
+	}
+	return false
 }
@@ -72,3 +72,162 @@ func TestWorkspace(t *testing.T) {
 		assert.DeepEqual(t, want, got)
 	})
 }
+
+func selectorTree() *i3.Tree {
+	return &i3.Tree{
+		Root: &i3.Node{
+			Type: "root",
+			Nodes: []*i3.Node{
+				{
+					Name: "__i3",
+					Type: "output",
+					Nodes: []*i3.Node{
+						{Type: "con", Name: "content", Nodes: []*i3.Node{workspaceNode(1, "__i3_scratch")}},
+					},
+				},
+				{
+					Name: "DP-1",
+					Type: "output",
+					Nodes: []*i3.Node{
+						workspaceNode(2, "1"),
+						workspaceNode(3, "3:mail"),
+						workspaceNode(4, "\U000f0293 4"),
+						workspaceNode(5, "7"),
+						workspaceNode(6, "chat"),
+						workspaceNode(7, "13"),
+						workspaceNode(8, "1-4"),
+					},
+				},
+			},
+		},
+	}
+}
+
+// workspaceNode is a workspace holding a window named after it, whose id is ten times its own
+func workspaceNode(id i3.NodeID, name string) *i3.Node {
+	return &i3.Node{
+		ID:    id,
+		Name:  name,
+		Type:  "workspace",
+		Nodes: []*i3.Node{{ID: id * 10, Type: "con", Name: name}},
+	}
+}
+
+// workspaceNames lists the workspaces left in the tree
+func workspaceNames(node *i3.Node) []string {
+	if node == nil {
+		return nil
+	}
+	if node.Type == "workspace" {
+		return []string{node.Name}
+	}
+
+	var found []string
+	for _, n := range node.Nodes {
+		found = append(found, workspaceNames(n)...)
+	}
+	return found
+}
+
+func TestWorkspaceSelectors(t *testing.T) {
+	cases := []struct {
+		selector string
+		want     []string
+	}{
+		{"chat", []string{"chat"}},
+		{"3", []string{"3:mail"}},
+		{"3:mail", []string{"3:mail"}},
+		{"\U000f0293 4", []string{"\U000f0293 4"}},
+		{"1", []string{"1"}},
+		{"2-7", []string{"3:mail", "7"}},
+		{"1-4", []string{"1-4"}},
+		{"1,3,7", []string{"1", "3:mail", "7"}},
+		{"1, 13", []string{"1", "13"}},
+		{"*mail", []string{"3:mail"}},
+		{"?", []string{"1", "7"}},
+		{"/^\\d+$/", []string{"1", "7", "13"}},
+		{"/CHAT/i", []string{"chat"}},
+		{"7,/^c/", []string{"7", "chat"}},
+		{"/^\\d{1,2}$/", []string{"1", "7", "13"}},
+		{"/^\\d{2}$/i,chat", []string{"chat", "13"}},
+		{"[1,3]*", []string{"1", "3:mail", "13", "1-4"}},
+		{"[7,c]*, 1", []string{"1", "7", "chat"}},
+		{"__i3_scratch", []string{"__i3_scratch"}},
+		{"__i3_scratch,1", []string{"__i3_scratch", "1"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.selector, func(t *testing.T) {
+			w := &prune.Ws{WsIndex: tt.selector}
+			got, err := w.TryPrune(selectorTree())
+
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tt.want, workspaceNames(got.Root))
+		})
+	}
+}
+
+func TestWorkspaceNoMatch(t *testing.T) {
+	w := &prune.Ws{WsIndex: "9"}
+	got, err := w.TryPrune(selectorTree())
+
+	assert.DeepEqual(t, &i3.Tree{}, got)
+	assert.DeepEqual(t, prune.NoWorkspaceError{
+		Selector:  "9",
+		Available: []string{"1", "3:mail", "\U000f0293 4", "7", "chat", "13", "1-4"},
+	}, err)
+	assert.Error(t, err, `no workspace matches "9", available: 1, 3:mail, `+"\U000f0293 4"+`, 7, chat, 13, 1-4`)
+
+	// Prune still renders nothing
+	assert.DeepEqual(t, &i3.Tree{}, w.Prune(selectorTree()))
+
+	_, err = w.TryPrune(&i3.Tree{})
+	assert.Error(t, err, `no workspace matches "9", there are no workspaces`)
+}
+
+func TestWorkspaceNoMatchByPattern(t *testing.T) {
+	// i3 only numbers workspaces starting with digits,
+	// and __i3_scratch is only selected by its name
+	for _, selector := range []string{"4", "/scratch/", "__i3*"} {
+		t.Run(selector, func(t *testing.T) {
+			w := &prune.Ws{WsIndex: selector}
+			_, err := w.TryPrune(selectorTree())
+
+			assert.ErrorType(t, err, prune.NoWorkspaceError{})
+		})
+	}
+}
+
+func TestWorkspaceBadSelector(t *testing.T) {
+	for _, selector := range []string{"/(/", "/abc", "[", "1,["} {
+		t.Run(selector, func(t *testing.T) {
+			w := &prune.Ws{WsIndex: selector}
+			_, err := w.TryPrune(selectorTree())
+
+			assert.Assert(t, err != nil)
+		})
+	}
+}
+
+func TestWorkspaceNum(t *testing.T) {
+	cases := []struct {
+		name string
+		want int
+	}{
+		{"3", 3},
+		{"3:mail", 3},
+		{"10: web", 10},
+		{"\U000f0293 1", -1},
+		{"mail 2", -1},
+		{"mail", -1},
+		{"mail2", -1},
+		{"007", 7},
+		{"", -1},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, prune.WorkspaceNum(tt.name))
+		})
+	}
+}
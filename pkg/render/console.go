@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/njhoffman/i3-tree/pkg/config"
+	"github.com/njhoffman/i3-tree/pkg/fetch"
 	"github.com/logrusorgru/aurora"
 	"go.i3wm.org/i3/v4"
 )
@@ -21,6 +22,26 @@ type console struct {
 	w      io.Writer
 	au     aurora.Aurora
 	config *config.Config
+
+	// MaxDepth is the number of levels rendered below workspaces, as with prune.Depth
+	// deeper nodes are summarized on a single line, 0 renders them all
+	MaxDepth int
+
+	// Match finds the nodes highlighted with the match formatting, when set
+	Match Matcher
+
+	// Extras are shown next to the marks, when set
+	Extras fetch.Extras
+
+	// nodes found by Match in the tree being rendered
+	matching map[*i3.Node]bool
+	// levels below their workspace of the nodes in one, when MaxDepth is set
+	wsLevels map[*i3.Node]int
+}
+
+// Matcher finds the nodes of a tree to highlight, e.g. prune.Match
+type Matcher interface {
+	Matching(tree *i3.Tree) map[*i3.Node]bool
 }
 
 func NewColoredConsole(w io.Writer) ColoredConsole {
@@ -53,56 +74,25 @@ func newConsole(w io.Writer, colors bool, cfg *config.Config) *console {
 
 func (t *console) Render(tree *i3.Tree) {
 	// Build a set of node IDs that are on the path to the focused node
-	focusedPath := t.buildFocusedPath(tree.Root)
-	t.print(tree.Root, "", "", 0, focusedPath, false, false)
-}
-
-// buildFocusedPath finds the path from root to the focused node
-// Returns a map of node IDs that are on this path
-func (t *console) buildFocusedPath(node *i3.Node) map[i3.NodeID]bool {
-	path := make(map[i3.NodeID]bool)
-	t.findFocusedPath(node, path)
-	return path
-}
-
-// findFocusedPath recursively searches for the focused node
-// and marks all nodes on the path to it
-// Returns true if this node or any of its children is/contains the focused node
-func (t *console) findFocusedPath(node *i3.Node, path map[i3.NodeID]bool) bool {
-	if node == nil {
-		return false
-	}
-
-	// Check if this node is focused
-	if node.Focused {
-		path[node.ID] = true
-		return true
-	}
+	focusedPath := buildFocusedPath(tree.Root)
 
-	// Check if any child contains the focused node (regular nodes)
-	for _, child := range node.Nodes {
-		if t.findFocusedPath(child, path) {
-			// This node is on the path to the focused node
-			path[node.ID] = true
-			return true
-		}
+	t.matching = nil
+	if t.Match != nil {
+		t.matching = t.Match.Matching(tree)
 	}
 
-	// Check floating nodes too
-	for _, child := range node.FloatingNodes {
-		if t.findFocusedPath(child, path) {
-			// This node is on the path to the focused node
-			path[node.ID] = true
-			return true
-		}
+	t.wsLevels = nil
+	if t.MaxDepth > 0 {
+		t.wsLevels = workspaceLevels(tree.Root)
 	}
 
-	return false
+	t.print(tree.Root, "", "", 0, focusedPath, false, false)
 }
 
 // formatWindowDetails formats additional window information like icons, class, marks, and title
 // Icons are displayed first, followed by class, title, and marks
-func (t *console) formatWindowDetails(node *i3.Node, isFloating bool) string {
+// Class and title (the name of containers) of matching nodes use the match formatting
+func (t *console) formatWindowDetails(node *i3.Node, isFloating bool, isMatch bool) string {
 	if node == nil {
 		return ""
 	}
@@ -151,8 +141,10 @@ func (t *console) formatWindowDetails(node *i3.Node, isFloating bool) string {
 	// Add window class if available (only for con type)
 	if t.config.Display.ShowWindowClass && node.Type == "con" && node.WindowProperties.Class != "" {
 		className := fmt.Sprintf("(%s)", node.WindowProperties.Class)
-		// Apply focus_class formatting if this is a focused node
-		if node.Focused {
+		// Apply match or focus_class formatting if this is a matching or focused node
+		if isMatch {
+			className = t.config.Formatting.Match.ApplyFormat(className, t.au)
+		} else if node.Focused {
 			className = t.config.Formatting.FocusClass.ApplyFormat(className, t.au)
 		} else {
 			className = t.config.Formatting.WindowClass.ApplyFormat(className, t.au)
@@ -168,7 +160,11 @@ func (t *console) formatWindowDetails(node *i3.Node, isFloating bool) string {
 		if len(title) > maxLen {
 			title = title[:maxLen-3] + "..."
 		}
-		formattedTitle := t.config.Formatting.WindowTitle.ApplyFormat(title, t.au)
+		titleFormat := t.config.Formatting.WindowTitle
+		if isMatch {
+			titleFormat = t.config.Formatting.Match
+		}
+		formattedTitle := titleFormat.ApplyFormat(title, t.au)
 		result += " " + formattedTitle
 	}
 
@@ -185,6 +181,12 @@ func (t *console) formatWindowDetails(node *i3.Node, isFloating bool) string {
 		result += " " + formattedMarks
 	}
 
+	// Add what the fetcher knows besides, e.g. {pid 4242, visible, scratchpad fresh}
+	if details := extraDetails(t.Extras[node.ID]); len(details) > 0 {
+		formattedDetails := t.config.Formatting.WindowDetails.ApplyFormat("{"+strings.Join(details, ", ")+"}", t.au)
+		result += " " + formattedDetails
+	}
+
 	return result
 }
 
@@ -216,10 +218,11 @@ func (t *console) print(node *i3.Node, prefix string, marker string, level int,
 		}
 
 		// Format the type as fcon
-		ftype := t.formatType(node, t.au, child.Focused, true)
+		isMatch := t.matching[node] || t.matching[child]
+		ftype := t.formatType(node, t.au, child.Focused, true, isMatch)
 
 		// Get child's window details (which will include icons first)
-		windowDetails := t.formatWindowDetails(child, true)
+		windowDetails := t.formatWindowDetails(child, true, isMatch)
 
 		fmt.Fprint(
 			t.w,
@@ -232,7 +235,7 @@ func (t *console) print(node *i3.Node, prefix string, marker string, level int,
 		return
 	}
 
-	ftype := t.formatType(node, t.au, isFocused, isFloating)
+	ftype := t.formatType(node, t.au, isFocused, isFloating, t.matching[node])
 	flayout := t.formatLayout(node, t.au, isFocused)
 
 	// Apply focus_branches formatting to marker
@@ -251,7 +254,7 @@ func (t *console) print(node *i3.Node, prefix string, marker string, level int,
 	}
 
 	// Format additional window details (class, marks, icons)
-	windowDetails := t.formatWindowDetails(node, isFloating)
+	windowDetails := t.formatWindowDetails(node, isFloating, t.matching[node])
 
 	fmt.Fprint(
 		t.w,
@@ -267,8 +270,13 @@ func (t *console) print(node *i3.Node, prefix string, marker string, level int,
 	allNodes := append([]*i3.Node{}, node.Nodes...)
 	allNodes = append(allNodes, node.FloatingNodes...)
 
+	// Too deep: summarize the children instead
+	if wsLevel, ok := t.wsLevels[node]; ok && wsLevel >= t.MaxDepth && len(allNodes) > 0 {
+		t.printSummary(allNodes, t.childPrefix(prefix, marker, level, hasFocusedSibling), focusedPath)
+		return
+	}
+
 	for i, n := range allNodes {
-		newPrefix := prefix
 		newMarker := ""
 
 		// Check if this is a floating node
@@ -293,29 +301,70 @@ func (t *console) print(node *i3.Node, prefix string, marker string, level int,
 			}
 		}
 
-		// Determine prefix for child based on current marker
-		// Check if current marker starts with ConnectH (we're a middle node)
-		// Use strings.HasPrefix to properly handle UTF-8 characters
-		if strings.HasPrefix(marker, t.config.Display.Branches.ConnectH) {
-			// This node has more children, so add trunk to prefix
-			// Highlight the trunk if this node has a focused sibling (passed from parent)
-			var trunkChar string
-			if hasFocusedSibling {
-				formattedVertical := t.config.Formatting.FocusBranches.ApplyFormat(t.config.Display.Branches.Vertical, t.au)
-				trunkChar = formattedVertical + "  "
-			} else {
-				trunkChar = t.config.Display.Branches.Vertical + "  "
-			}
-			newPrefix = newPrefix + trunkChar
+		newPrefix := t.childPrefix(prefix, marker, level, hasFocusedSibling)
+
+		t.print(n, newPrefix, newMarker, level+1, focusedPath, childIsFloating, anySiblingOnFocusedPath)
+	}
+}
+
+// childPrefix is the prefix of the children of a node, based on the node's marker
+func (t *console) childPrefix(prefix string, marker string, level int, hasFocusedSibling bool) string {
+	// Determine prefix for child based on current marker
+	// Check if current marker starts with ConnectH (we're a middle node)
+	// Use strings.HasPrefix to properly handle UTF-8 characters
+	if strings.HasPrefix(marker, t.config.Display.Branches.ConnectH) {
+		// This node has more children, so add trunk to prefix
+		// Highlight the trunk if this node has a focused sibling (passed from parent)
+		var trunkChar string
+		if hasFocusedSibling {
+			formattedVertical := t.config.Formatting.FocusBranches.ApplyFormat(t.config.Display.Branches.Vertical, t.au)
+			trunkChar = formattedVertical + "  "
 		} else {
-			// don't indent starting from root
-			if level == 0 {
-				newPrefix = ""
-			} else {
-				newPrefix = newPrefix + "   "
-			}
+			trunkChar = t.config.Display.Branches.Vertical + "  "
 		}
+		return prefix + trunkChar
+	}
 
-		t.print(n, newPrefix, newMarker, level+1, focusedPath, childIsFloating, anySiblingOnFocusedPath)
+	// don't indent starting from root
+	if level == 0 {
+		return ""
+	}
+	return prefix + "   "
+}
+
+// printSummary prints a single line in place of nodes too deep to be rendered
+// e.g. "… 7 windows: Firefox×3, Alacritty×4"
+func (t *console) printSummary(nodes []*i3.Node, prefix string, focusedPath map[i3.NodeID]bool) {
+	marker := t.config.Display.Branches.ConnectV + t.config.Display.Branches.Horizontal
+
+	for _, n := range nodes {
+		if focusedPath[n.ID] {
+			marker = t.config.Formatting.FocusBranches.ApplyFormat(marker, t.au)
+			break
+		}
+	}
+
+	fmt.Fprint(t.w, prefix, marker, summarize(nodes, t.config, t.au), "\n")
+}
+
+// extraDetails lists what is set in an Extra, shown as {pid 4242, visible}
+func extraDetails(e fetch.Extra) []string {
+	var details []string
+	if e.PID != 0 {
+		details = append(details, fmt.Sprintf("pid %d", e.PID))
+	}
+	if e.Visible != nil {
+		if *e.Visible {
+			details = append(details, "visible")
+		} else {
+			details = append(details, "hidden")
+		}
+	}
+	if e.InhibitIdle {
+		details = append(details, "inhibits idle")
+	}
+	if e.InScratchpad() {
+		details = append(details, "scratchpad "+e.ScratchpadState)
 	}
+	return details
 }
@@ -3,7 +3,6 @@ package render
 import (
 	"fmt"
 
-	"github.com/njhoffman/i3-tree/pkg/config"
 	"github.com/logrusorgru/aurora"
 	"go.i3wm.org/i3/v4"
 )
@@ -39,7 +38,7 @@ func (t *console) formatLayout(node *i3.Node, au aurora.Aurora, isFocused bool)
 	return s
 }
 
-func (t *console) formatType(node *i3.Node, au aurora.Aurora, isFocused bool, isFloating bool) string {
+func (t *console) formatType(node *i3.Node, au aurora.Aurora, isFocused bool, isFloating bool, isMatch bool) string {
 	if node == nil {
 		return ""
 	}
@@ -53,21 +52,10 @@ func (t *console) formatType(node *i3.Node, au aurora.Aurora, isFocused bool, is
 			s = "fcon"
 		}
 
-		// Use config formatting for node types
-		var nodeFormat *config.NodeFormat
-		switch nodeType {
-		case "workspace":
-			nodeFormat = &t.config.Formatting.Workspace
-		case "con":
-			nodeFormat = &t.config.Formatting.Con
-		case "floating_con":
-			nodeFormat = &t.config.Formatting.FloatCon
-		case "output":
-			nodeFormat = &t.config.Formatting.Output
-		case "root":
-			nodeFormat = &t.config.Formatting.Root
-		default:
-			return s
+		// Use config formatting for node types, or the match one for matching nodes
+		nodeFormat := nodeTypeFormat(t.config, nodeType)
+		if isMatch {
+			nodeFormat = &t.config.Formatting.Match
 		}
 
 		if nodeFormat != nil {
@@ -5,177 +5,50 @@ import (
 	"io"
 	"testing"
 
+	"github.com/njhoffman/i3-tree/pkg/fetch"
 	"github.com/njhoffman/i3-tree/pkg/render"
 	"github.com/stretchr/testify/assert"
 	"go.i3wm.org/i3/v4"
 )
 
 // Generates a fake tree used for testing
-func fakeTree() i3.Tree {
-	// Horizontal Split
-	ws1 := &i3.Node{
-		Name:   "1",
-		Type:   i3.NodeType(i3.WorkspaceNode),
-		Layout: i3.Layout(i3.SplitH),
-		Nodes: []*i3.Node{
-			{
-				Name: "Mozilla Firefox",
-				Type: i3.NodeType(i3.Con),
-			},
-		},
-	}
-
-	// Stacked
-	ws2 := &i3.Node{
-		Name:   "2",
-		Type:   i3.NodeType(i3.WorkspaceNode),
-		Layout: i3.Layout(i3.Stacked),
-		Nodes: []*i3.Node{
-			{
-				Name: "Mozilla Firefox",
-				Type: i3.NodeType(i3.Con),
-			},
-			{
-				Name: "Google Chrome",
-				Type: i3.NodeType(i3.Con),
-			},
-			{
-				Name: "Chromium",
-				Type: i3.NodeType(i3.Con),
-			},
-		},
-	}
-
-	// Vertical Split
-	ws3 := &i3.Node{
-		Name:   "3",
-		Type:   i3.NodeType(i3.WorkspaceNode),
-		Layout: i3.Layout(i3.SplitV),
-		Nodes: []*i3.Node{
-			{
-				Name: "Mozilla Firefox",
-				Type: i3.NodeType(i3.Con),
-			},
-			{
-				Name: "VLC media player",
-				Type: i3.NodeType(i3.Con),
-			},
-		},
-	}
-
-	// Tabbed
-	ws4 := &i3.Node{
-		Name:   "4",
-		Type:   i3.NodeType(i3.WorkspaceNode),
-		Layout: i3.Layout(i3.Tabbed),
-		Nodes: []*i3.Node{
-			{
-				Name: "Mozilla Firefox",
-				Type: i3.NodeType(i3.Con),
-			},
-			{
-				Name: "VLC media player",
-				Type: i3.NodeType(i3.Con),
-			},
-			{
-				Name: "Slack",
-				Type: i3.NodeType(i3.Con),
-			},
-		},
-	}
-
-	// Slightly more complex with nested panes
-	ws5 := &i3.Node{
-		Name:   "5",
-		Type:   i3.NodeType(i3.WorkspaceNode),
-		Layout: i3.Layout(i3.SplitH),
-		Nodes: []*i3.Node{
-			{
-				Type:   i3.NodeType(i3.Con),
-				Layout: i3.Layout(i3.SplitV),
-				Nodes: []*i3.Node{
-					{
-						Name: "/bin/bash",
-						Type: i3.NodeType(i3.Con),
-					},
-					{
-						Name: "/bin/bash",
-						Type: i3.NodeType(i3.Con),
-					},
-				},
-			},
-			{
-				Type:   i3.NodeType(i3.Con),
-				Layout: i3.Layout(i3.SplitV),
-				Nodes: []*i3.Node{
-					{
-						Name: "/bin/bash",
-						Type: i3.NodeType(i3.Con),
-					},
-					{
-						Name: "/bin/bash",
-						Type: i3.NodeType(i3.Con),
-					},
-				},
-			},
-		},
-	}
-
-	node := i3.Node{
-		Name: "root",
-		Type: i3.NodeType(i3.Root),
-
-		Nodes: []*i3.Node{
-			{
-				Name:   "HDMI-0",
-				Type:   i3.NodeType(i3.OutputNode),
-				Layout: i3.Layout(i3.OutputLayout),
-				Nodes: []*i3.Node{
-					ws1,
-					ws2,
-					ws3,
-					ws4,
-					ws5,
-				},
-			},
-		},
-	}
-
-	return i3.Tree{
-		Root: &node,
-	}
-}
-
 func TestConRendererNoColor(t *testing.T) {
 	want := `[root] root
-└──[output][output] HDMI-0
-   ├──[workspace][splith] 1
-   │  └──[con] Mozilla Firefox
-   ├──[workspace][stacked] 2
-   │  ├──[con] Mozilla Firefox
-   │  ├──[con] Google Chrome
-   │  └──[con] Chromium
-   ├──[workspace][splitv] 3
-   │  ├──[con] Mozilla Firefox
-   │  └──[con] VLC media player
-   ├──[workspace][tabbed] 4
-   │  ├──[con] Mozilla Firefox
-   │  ├──[con] VLC media player
-   │  └──[con] Slack
-   └──[workspace][splith] 5
+├──[output][output] HDMI-0
+│  ├──[workspace][splith] 1
+│  │  └──[con] Reddit.com - Mozilla Firefox
+│  ├──[workspace][stacked] 2
+│  │  ├──[con] Twitter.com - Mozilla Firefox
+│  │  ├──[con] Stackoverflow.com - Google Chrome
+│  │  └──[con] duckduckgo.com - Chromium
+│  ├──[workspace][splitv] 3
+│  │  ├──[con] Mozilla Firefox
+│  │  └──[con] VLC media player
+│  ├──[workspace][tabbed] 4
+│  │  ├──[con] kubernetes.io - Mozilla Firefox
+│  │  ├──[con] VLC media player
+│  │  └──[con] Slack
+│  └──[workspace][splith] 5
+│     ├──[con][splitv]
+│     │  ├──[con] /bin/bash
+│     │  └──[con] /bin/bash
+│     └──[con][splitv]
+│        ├──[con] /bin/bash
+│        └──[con] /bin/bash
+└──[output][output] HDMI-1
+   └──[workspace][splith] 6
       ├──[con][splitv]
-      │  ├──[con] /bin/bash
-      │  └──[con] /bin/bash
+      │  └──[con] VLC media player
       └──[con][splitv]
          ├──[con] /bin/bash
          └──[con] /bin/bash
 `
 
-	tree := fakeTree()
+	tree := fetch.FakeTree()
 
 	var writer bytes.Buffer
 	r := render.NewMonochromaticConsole(io.Writer(&writer))
-	r.Render(&tree)
+	r.Render(tree)
 
 	got := writer.String()
 	assert.Equal(t, want, got)
@@ -185,33 +58,40 @@ func TestConRendererWithColor(t *testing.T) {
 	// Hacky but works for now
 	// This blob was generated by piping the test result to a file
 	want := "[root][\x1b[33m\x1b[0m] root\n" +
-		"└──[\x1b[35moutput\x1b[0m][\x1b[33moutput\x1b[0m] HDMI-0\n" +
-		"   ├──[\x1b[36mworkspace\x1b[0m][\x1b[33msplith\x1b[0m] 1\n" +
-		"   │  └──[\x1b[34mcon\x1b[0m] Mozilla Firefox\n" +
-		"   ├──[\x1b[36mworkspace\x1b[0m][\x1b[33mstacked\x1b[0m] 2\n" +
-		"   │  ├──[\x1b[34mcon\x1b[0m] Mozilla Firefox\n" +
-		"   │  ├──[\x1b[34mcon\x1b[0m] Google Chrome\n" +
-		"   │  └──[\x1b[34mcon\x1b[0m] Chromium\n" +
-		"   ├──[\x1b[36mworkspace\x1b[0m][\x1b[33msplitv\x1b[0m] 3\n" +
-		"   │  ├──[\x1b[34mcon\x1b[0m] Mozilla Firefox\n" +
-		"   │  └──[\x1b[34mcon\x1b[0m] VLC media player\n" +
-		"   ├──[\x1b[36mworkspace\x1b[0m][\x1b[33mtabbed\x1b[0m] 4\n" +
-		"   │  ├──[\x1b[34mcon\x1b[0m] Mozilla Firefox\n" +
-		"   │  ├──[\x1b[34mcon\x1b[0m] VLC media player\n" +
-		"   │  └──[\x1b[34mcon\x1b[0m] Slack\n" +
-		"   └──[\x1b[36mworkspace\x1b[0m][\x1b[33msplith\x1b[0m] 5\n" +
-		"      ├──[\x1b[34mcon\x1b[0m][\x1b[33msplitv\x1b[0m]\n" +
-		"      │  ├──[\x1b[34mcon\x1b[0m] /bin/bash\n" +
-		"      │  └──[\x1b[34mcon\x1b[0m] /bin/bash\n" +
+		"\x1b[1;38;5;80m├\x1b[0m──[\x1b[35moutput\x1b[0m][\x1b[33moutput\x1b[0m] HDMI-0\n" +
+		"\x1b[1;38;5;80m│\x1b[0m  ├──[\x1b[36mworkspace\x1b[0m][\x1b[33msplith\x1b[0m] 1\n" +
+		"\x1b[1;38;5;80m│\x1b[0m  │  └──[\x1b[34mcon\x1b[0m] Reddit.com - Mozilla Firefox\n" +
+		"\x1b[1;38;5;80m│\x1b[0m  ├──[\x1b[36mworkspace\x1b[0m][\x1b[33mstacked\x1b[0m] 2\n" +
+		"\x1b[1;38;5;80m│\x1b[0m  │  ├──[\x1b[34mcon\x1b[0m] Twitter.com - Mozilla Firefox\n" +
+		"\x1b[1;38;5;80m│\x1b[0m  │  ├──[\x1b[34mcon\x1b[0m] Stackoverflow.com - Google Chrome\n" +
+		"\x1b[1;38;5;80m│\x1b[0m  │  └──[\x1b[34mcon\x1b[0m] duckduckgo.com - Chromium\n" +
+		"\x1b[1;38;5;80m│\x1b[0m  ├──[\x1b[36mworkspace\x1b[0m][\x1b[33msplitv\x1b[0m] 3\n" +
+		"\x1b[1;38;5;80m│\x1b[0m  │  ├──[\x1b[34mcon\x1b[0m] Mozilla Firefox\n" +
+		"\x1b[1;38;5;80m│\x1b[0m  │  └──[\x1b[34mcon\x1b[0m] VLC media player\n" +
+		"\x1b[1;38;5;80m│\x1b[0m  ├──[\x1b[36mworkspace\x1b[0m][\x1b[33mtabbed\x1b[0m] 4\n" +
+		"\x1b[1;38;5;80m│\x1b[0m  │  ├──[\x1b[34mcon\x1b[0m] kubernetes.io - Mozilla Firefox\n" +
+		"\x1b[1;38;5;80m│\x1b[0m  │  ├──[\x1b[34mcon\x1b[0m] VLC media player\n" +
+		"\x1b[1;38;5;80m│\x1b[0m  │  └──[\x1b[34mcon\x1b[0m] Slack\n" +
+		"\x1b[1;38;5;80m│\x1b[0m  └──[\x1b[36mworkspace\x1b[0m][\x1b[33msplith\x1b[0m] 5\n" +
+		"\x1b[1;38;5;80m│\x1b[0m     ├──[\x1b[34mcon\x1b[0m][\x1b[33msplitv\x1b[0m]\n" +
+		"\x1b[1;38;5;80m│\x1b[0m     │  ├──[\x1b[34mcon\x1b[0m] /bin/bash\n" +
+		"\x1b[1;38;5;80m│\x1b[0m     │  └──[\x1b[34mcon\x1b[0m] /bin/bash\n" +
+		"\x1b[1;38;5;80m│\x1b[0m     └──[\x1b[34mcon\x1b[0m][\x1b[33msplitv\x1b[0m]\n" +
+		"\x1b[1;38;5;80m│\x1b[0m        ├──[\x1b[34mcon\x1b[0m] /bin/bash\n" +
+		"\x1b[1;38;5;80m│\x1b[0m        └──[\x1b[34mcon\x1b[0m] /bin/bash\n" +
+		"\x1b[1;38;5;80m└──\x1b[0m[\x1b[35moutput\x1b[0m][\x1b[33moutput\x1b[0m] HDMI-1\n" +
+		"   \x1b[1;38;5;80m└──\x1b[0m[\x1b[36mworkspace\x1b[0m][\x1b[33msplith\x1b[0m] 6\n" +
+		"      \x1b[1;38;5;80m├──\x1b[0m[\x1b[34mcon\x1b[0m][\x1b[33msplitv\x1b[0m]\n" +
+		"      │  \x1b[1;38;5;80m└──\x1b[0m\x1b[1m[\x1b[0m\x1b[1;34mcon\x1b[0m\x1b[1m]\x1b[0m VLC media player\n" +
 		"      └──[\x1b[34mcon\x1b[0m][\x1b[33msplitv\x1b[0m]\n" +
 		"         ├──[\x1b[34mcon\x1b[0m] /bin/bash\n" +
 		"         └──[\x1b[34mcon\x1b[0m] /bin/bash\n"
 
-	tree := fakeTree()
+	tree := fetch.FakeTree()
 
 	var writer bytes.Buffer
 	r := render.NewColoredConsole(io.Writer(&writer))
-	r.Render(&tree)
+	r.Render(tree)
 
 	got := writer.String()
 	assert.Equal(t, want, (got))
@@ -380,6 +260,38 @@ func TestConRendererWithWindowDetails(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func TestConRendererWithExtras(t *testing.T) {
+	tree := i3.Tree{
+		Root: &i3.Node{
+			ID:   1,
+			Name: "root",
+			Type: i3.NodeType(i3.Root),
+			Nodes: []*i3.Node{
+				{ID: 2, Name: "foot", Type: i3.NodeType(i3.Con), Marks: []string{"edit"}},
+				{ID: 3, Name: "mpv", Type: i3.NodeType(i3.Con)},
+				{ID: 4, Name: "KeePassXC", Type: i3.NodeType(i3.Con)},
+			},
+		},
+	}
+
+	visible, hidden := true, false
+	want := "[root] root\n" +
+		"├──[con] foot [edit] {pid 4242, visible}\n" +
+		"├──[con] mpv {hidden, inhibits idle}\n" +
+		"└──[con] KeePassXC {scratchpad fresh}\n"
+
+	var writer bytes.Buffer
+	r := render.NewMonochromaticConsole(io.Writer(&writer))
+	r.Extras = fetch.Extras{
+		2: {PID: 4242, Visible: &visible},
+		3: {Visible: &hidden, InhibitIdle: true},
+		4: {ScratchpadState: "fresh"},
+	}
+	r.Render(&tree)
+
+	assert.Equal(t, want, writer.String())
+}
+
 func TestConRendererWithFloatingWindows(t *testing.T) {
 	// Create a tree with floating windows
 	floatingWindow := &i3.Node{
@@ -444,3 +356,207 @@ func TestConRendererWithFloatingWindows(t *testing.T) {
 	got := writer.String()
 	assert.Equal(t, want, got)
 }
+
+func TestConRendererNoColorFromDump(t *testing.T) {
+	want := `[root][splith] root
+├──[output][output] __i3
+│  └──[con][splith] content
+│     └──[workspace] 󰊓 __i3_scratch
+│        └──[fcon] 󰭽 (KeePassXC) KeePassXC [scratch]
+└──[output][output] eDP-1
+   ├──[dockarea][dockarea] topdock
+   │  └──[con] (Polybar) polybar-top_eDP-1
+   └──[con][splith] content
+      ├──[workspace][splith] 󰊓 1
+      │  ├──[con] (Alacritty) main [_last]
+      │  └──[con] (firefox) Jira - Mozilla Firefox
+      └──[workspace][tabbed] 󰊓  2:mail
+         └──[con]  (thunderbird) Inbox - Mozilla Thunderbird
+`
+
+	tree, err := fetch.FromFile{Path: "../fetch/testdata/get_tree.json"}.Fetch()
+	assert.NoError(t, err)
+
+	var writer bytes.Buffer
+	r := render.NewMonochromaticConsole(io.Writer(&writer))
+	r.Render(&tree)
+
+	got := writer.String()
+	assert.Equal(t, want, got)
+}
+
+func TestConRendererNoColorWithMaxDepth(t *testing.T) {
+	want := `[root] root
+├──[output][output] HDMI-0
+│  ├──[workspace][splith] 1
+│  │  └──[con] Reddit.com - Mozilla Firefox
+│  ├──[workspace][stacked] 2
+│  │  ├──[con] Twitter.com - Mozilla Firefox
+│  │  ├──[con] Stackoverflow.com - Google Chrome
+│  │  └──[con] duckduckgo.com - Chromium
+│  ├──[workspace][splitv] 3
+│  │  ├──[con] Mozilla Firefox
+│  │  └──[con] VLC media player
+│  ├──[workspace][tabbed] 4
+│  │  ├──[con] kubernetes.io - Mozilla Firefox
+│  │  ├──[con] VLC media player
+│  │  └──[con] Slack
+│  └──[workspace][splith] 5
+│     ├──[con][splitv]
+│     │  └──… 2 windows: /bin/bash×2
+│     └──[con][splitv]
+│        └──… 2 windows: /bin/bash×2
+└──[output][output] HDMI-1
+   └──[workspace][splith] 6
+      ├──[con][splitv]
+      │  └──… 1 window: VLC media player
+      └──[con][splitv]
+         └──… 2 windows: /bin/bash×2
+`
+
+	tree := fetch.FakeTree()
+
+	var writer bytes.Buffer
+	r := render.NewMonochromaticConsole(io.Writer(&writer))
+	r.MaxDepth = 1
+	r.Render(tree)
+
+	assert.Equal(t, want, writer.String())
+}
+
+func TestConRendererNoColorWithMaxDepthGroupsByClass(t *testing.T) {
+	window := func(class string, title string) *i3.Node {
+		return &i3.Node{
+			Name:             title,
+			Type:             i3.NodeType(i3.Con),
+			WindowProperties: i3.WindowProperties{Class: class},
+		}
+	}
+
+	tree := i3.Tree{Root: &i3.Node{
+		Name: "root",
+		Type: i3.NodeType(i3.Root),
+		Nodes: []*i3.Node{
+			{
+				Name:   "1",
+				Type:   i3.NodeType(i3.WorkspaceNode),
+				Layout: i3.Layout(i3.SplitH),
+				Nodes: []*i3.Node{
+					{
+						Type:   i3.NodeType(i3.Con),
+						Layout: i3.Layout(i3.SplitV),
+						Nodes: []*i3.Node{
+							window("Firefox", "Jira"),
+							window("Alacritty", "vim"),
+							window("Firefox", "GitHub"),
+						},
+					},
+					{
+						Type:   i3.NodeType(i3.Con),
+						Layout: i3.Layout(i3.Tabbed),
+						Nodes: []*i3.Node{
+							window("Alacritty", "htop"),
+							window("", "untitled"),
+						},
+					},
+				},
+				FloatingNodes: []*i3.Node{
+					{
+						Type:  "floating_con",
+						Nodes: []*i3.Node{window("Firefox", "Picture-in-Picture")},
+					},
+				},
+			},
+			{
+				Name:   "2",
+				Type:   i3.NodeType(i3.WorkspaceNode),
+				Layout: i3.Layout(i3.SplitH),
+				Nodes: []*i3.Node{
+					{Type: i3.NodeType(i3.Con), Layout: i3.Layout(i3.SplitV), Nodes: []*i3.Node{
+						{Type: i3.NodeType(i3.Con), Layout: i3.Layout(i3.SplitH), Nodes: []*i3.Node{}},
+					}},
+				},
+			},
+		},
+	}}
+
+	want := `[root] root
+├──[workspace][splith] 1
+│  ├──[con][splitv]
+│  │  └──… 3 windows: Firefox×2, Alacritty
+│  ├──[con][tabbed]
+│  │  └──… 2 windows: Alacritty, untitled
+│  └──[fcon] 󰭽 (Firefox) Picture-in-Picture
+└──[workspace][splith] 2
+   └──[con][splitv]
+      └──… 1 container
+`
+
+	var writer bytes.Buffer
+	r := render.NewMonochromaticConsole(io.Writer(&writer))
+	r.MaxDepth = 1
+	r.Render(&tree)
+
+	assert.Equal(t, want, writer.String())
+}
+
+type titleMatcher string
+
+func (m titleMatcher) Matching(tree *i3.Tree) map[*i3.Node]bool {
+	matching := make(map[*i3.Node]bool)
+
+	var walk func(n *i3.Node)
+	walk = func(n *i3.Node) {
+		if n.Name == string(m) {
+			matching[n] = true
+		}
+		for _, c := range n.Nodes {
+			walk(c)
+		}
+	}
+	walk(tree.Root)
+
+	return matching
+}
+
+func TestConRendererWithMatch(t *testing.T) {
+	want := "[root][\x1b[33m\x1b[0m] root\n" +
+		"└──[\x1b[35moutput\x1b[0m][\x1b[33moutput\x1b[0m] HDMI-0\n" +
+		"   └──[\x1b[36mworkspace\x1b[0m][\x1b[33mstacked\x1b[0m] 2\n" +
+		"      ├──[\x1b[34mcon\x1b[0m] Twitter.com - Mozilla Firefox\n" +
+		"      ├──[\x1b[1;4;93mcon\x1b[0m] \x1b[1;4;93mStackoverflow.com - Google Chrome\x1b[0m\n" +
+		"      └──[\x1b[34mcon\x1b[0m] duckduckgo.com - Chromium\n"
+
+	tree := fetch.FakeTree()
+	tree.Root.Nodes = tree.Root.Nodes[:1]
+	output := tree.Root.Nodes[0]
+	output.Nodes = output.Nodes[1:2]
+
+	var writer bytes.Buffer
+	r := render.NewColoredConsole(io.Writer(&writer))
+	r.Match = titleMatcher("Stackoverflow.com - Google Chrome")
+	r.Render(tree)
+
+	assert.Equal(t, want, writer.String())
+}
+
+func TestConRendererWithMatchingContainer(t *testing.T) {
+	want := "[root][\x1b[33m\x1b[0m] root\n" +
+		"└──[\x1b[35moutput\x1b[0m][\x1b[33moutput\x1b[0m] HDMI-0\n" +
+		"   └──[\x1b[1;4;93mworkspace\x1b[0m][\x1b[33mstacked\x1b[0m] \x1b[1;4;93m2\x1b[0m\n" +
+		"      ├──[\x1b[34mcon\x1b[0m] Twitter.com - Mozilla Firefox\n" +
+		"      ├──[\x1b[34mcon\x1b[0m] Stackoverflow.com - Google Chrome\n" +
+		"      └──[\x1b[34mcon\x1b[0m] duckduckgo.com - Chromium\n"
+
+	tree := fetch.FakeTree()
+	tree.Root.Nodes = tree.Root.Nodes[:1]
+	output := tree.Root.Nodes[0]
+	output.Nodes = output.Nodes[1:2]
+
+	var writer bytes.Buffer
+	r := render.NewColoredConsole(io.Writer(&writer))
+	r.Match = titleMatcher("2")
+	r.Render(tree)
+
+	assert.Equal(t, want, writer.String())
+}
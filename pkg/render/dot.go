@@ -0,0 +1,164 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/njhoffman/i3-tree/pkg/config"
+	"go.i3wm.org/i3/v4"
+)
+
+// Dot renders the tree as a Graphviz digraph
+// e.g. i3-tree --render=dot | dot -Tsvg > tree.svg
+type Dot struct {
+	w      io.Writer
+	config *config.Config
+}
+
+// dotShapes maps every node type to a Graphviz node shape
+var dotShapes = map[i3.NodeType]string{
+	"root":         "doubleoctagon",
+	"output":       "box3d",
+	"workspace":    "tab",
+	"dockarea":     "cds",
+	"con":          "box",
+	"floating_con": "box",
+}
+
+func NewDot(w io.Writer) Dot {
+	return NewDotWithConfig(w, config.DefaultConfig())
+}
+
+func NewDotWithConfig(w io.Writer, cfg *config.Config) Dot {
+	return Dot{
+		w:      w,
+		config: cfg,
+	}
+}
+
+func (d Dot) Render(tree *i3.Tree) {
+	focusedPath := buildFocusedPath(tree.Root)
+
+	fmt.Fprintln(d.w, "digraph i3 {")
+	fmt.Fprintln(d.w, `  node [fontname="monospace"];`)
+	fmt.Fprintln(d.w, `  edge [fontname="monospace", fontsize=10];`)
+
+	if tree.Root != nil {
+		seq := 0
+		d.print(tree.Root, nil, &seq, focusedPath, false)
+	}
+
+	fmt.Fprintln(d.w, "}")
+}
+
+// print writes node and its children, linking node to parent
+// seq generates unique graph ids, since i3 ids are not always set (e.g. mock data)
+func (d Dot) print(node *i3.Node, parent *dotParent, seq *int, focusedPath map[i3.NodeID]bool, isFloating bool) {
+	id := fmt.Sprintf("n%d", *seq)
+	*seq++
+
+	isOnFocusedPath := focusedPath[node.ID]
+	isFloating = isFloating || node.Type == "floating_con"
+
+	attrs := []string{
+		"label=" + dotQuote(d.label(node, isFloating)),
+	}
+	if shape, ok := dotShapes[node.Type]; ok {
+		attrs = append(attrs, "shape="+shape)
+	}
+
+	style := "rounded"
+	if isFloating {
+		style += ",dashed"
+	}
+	if node.Focused {
+		style += ",bold"
+	}
+
+	if nf := nodeTypeFormat(d.config, node.Type); nf != nil {
+		if color := nf.ForegroundHex(); color != "" {
+			attrs = append(attrs, "color="+dotQuote(color))
+		}
+		if color := nf.BackgroundHex(); color != "" {
+			style += ",filled"
+			attrs = append(attrs, "fillcolor="+dotQuote(color))
+		}
+	}
+	attrs = append(attrs, "style="+dotQuote(style))
+
+	fmt.Fprintf(d.w, "  %s [%s];\n", id, strings.Join(attrs, ", "))
+
+	if parent != nil {
+		d.printEdge(parent, id, isOnFocusedPath)
+	}
+
+	self := &dotParent{id: id, layout: node.Layout}
+	for _, n := range node.Nodes {
+		d.print(n, self, seq, focusedPath, isFloating)
+	}
+
+	// floating windows are not arranged by the layout
+	floatParent := &dotParent{id: id}
+	for _, n := range node.FloatingNodes {
+		d.print(n, floatParent, seq, focusedPath, true)
+	}
+}
+
+// dotParent is what an edge needs to know about the parent node
+type dotParent struct {
+	id     string
+	layout i3.Layout
+}
+
+// printEdge links parent to child
+// the parent's layout decides how children are arranged, so it labels the edge
+func (d Dot) printEdge(parent *dotParent, child string, isOnFocusedPath bool) {
+	var attrs []string
+
+	if parent.layout != "" {
+		attrs = append(attrs, "label="+dotQuote(string(parent.layout)))
+		if color := d.config.Formatting.WindowLayout.ForegroundHex(); color != "" {
+			attrs = append(attrs, "fontcolor="+dotQuote(color))
+		}
+	}
+
+	if isOnFocusedPath {
+		attrs = append(attrs, "penwidth=2")
+		if color := d.config.Formatting.FocusBranches.ForegroundHex(); color != "" {
+			attrs = append(attrs, "color="+dotQuote(color))
+		}
+	}
+
+	if len(attrs) > 0 {
+		fmt.Fprintf(d.w, "  %s -> %s [%s];\n", parent.id, child, strings.Join(attrs, ", "))
+		return
+	}
+	fmt.Fprintf(d.w, "  %s -> %s;\n", parent.id, child)
+}
+
+// label describes a node in up to three lines: [type], (class) and name
+func (d Dot) label(node *i3.Node, isFloating bool) string {
+	nodeType := string(node.Type)
+	if isFloating && node.Type == "con" {
+		nodeType = "fcon"
+	}
+
+	lines := []string{"[" + nodeType + "]"}
+	if node.WindowProperties.Class != "" {
+		lines = append(lines, "("+node.WindowProperties.Class+")")
+	}
+	if node.Name != "" {
+		lines = append(lines, node.Name)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// dotQuote quotes a string as a DOT ID
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
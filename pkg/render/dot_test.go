@@ -0,0 +1,96 @@
+package render_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/render"
+	"github.com/stretchr/testify/assert"
+	"go.i3wm.org/i3/v4"
+)
+
+func TestDotRenderer(t *testing.T) {
+	tree := i3.Tree{
+		Root: &i3.Node{
+			ID:     1,
+			Name:   "root",
+			Type:   i3.NodeType(i3.Root),
+			Layout: i3.Layout(i3.SplitH),
+			Nodes: []*i3.Node{
+				{
+					ID:     2,
+					Name:   "HDMI-0",
+					Type:   i3.NodeType(i3.OutputNode),
+					Layout: i3.Layout(i3.OutputLayout),
+					Nodes: []*i3.Node{
+						{
+							ID:     3,
+							Name:   "1",
+							Type:   i3.NodeType(i3.WorkspaceNode),
+							Layout: i3.Layout(i3.Tabbed),
+							Nodes: []*i3.Node{
+								{
+									ID:      4,
+									Name:    `say "hi"`,
+									Type:    i3.NodeType(i3.Con),
+									Focused: true,
+									WindowProperties: i3.WindowProperties{
+										Class: "Alacritty",
+									},
+								},
+								{
+									ID:   5,
+									Name: "Slack",
+									Type: i3.NodeType(i3.Con),
+								},
+							},
+							FloatingNodes: []*i3.Node{
+								{
+									ID:   6,
+									Type: "floating_con",
+									Nodes: []*i3.Node{
+										{ID: 7, Name: "mpv", Type: i3.NodeType(i3.Con)},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	want := `digraph i3 {
+  node [fontname="monospace"];
+  edge [fontname="monospace", fontsize=10];
+  n0 [label="[root]\nroot", shape=doubleoctagon, style="rounded"];
+  n1 [label="[output]\nHDMI-0", shape=box3d, color="#cd00cd", style="rounded"];
+  n0 -> n1 [label="splith", fontcolor="#cdcd00", penwidth=2, color="#5fd7d7"];
+  n2 [label="[workspace]\n1", shape=tab, color="#00cdcd", style="rounded"];
+  n1 -> n2 [label="output", fontcolor="#cdcd00", penwidth=2, color="#5fd7d7"];
+  n3 [label="[con]\n(Alacritty)\nsay \"hi\"", shape=box, color="#0000ee", style="rounded,bold"];
+  n2 -> n3 [label="tabbed", fontcolor="#cdcd00", penwidth=2, color="#5fd7d7"];
+  n4 [label="[con]\nSlack", shape=box, color="#0000ee", style="rounded"];
+  n2 -> n4 [label="tabbed", fontcolor="#cdcd00"];
+  n5 [label="[floating_con]", shape=box, color="#0000ee", style="rounded,dashed"];
+  n2 -> n5;
+  n6 [label="[fcon]\nmpv", shape=box, color="#0000ee", style="rounded,dashed"];
+  n5 -> n6;
+}
+`
+
+	var writer bytes.Buffer
+	r := render.NewDot(io.Writer(&writer))
+	r.Render(&tree)
+
+	assert.Equal(t, want, writer.String())
+}
+
+func TestDotRendererEmptyTree(t *testing.T) {
+	var writer bytes.Buffer
+	r := render.NewDot(io.Writer(&writer))
+	r.Render(&i3.Tree{})
+
+	assert.Equal(t, "digraph i3 {\n  node [fontname=\"monospace\"];\n  edge [fontname=\"monospace\", fontsize=10];\n}\n", writer.String())
+}
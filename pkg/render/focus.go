@@ -0,0 +1,68 @@
+package render
+
+import (
+	"github.com/njhoffman/i3-tree/pkg/config"
+	"go.i3wm.org/i3/v4"
+)
+
+// buildFocusedPath finds the path from root to the focused node
+// Returns a map of node IDs that are on this path
+func buildFocusedPath(node *i3.Node) map[i3.NodeID]bool {
+	path := make(map[i3.NodeID]bool)
+	findFocusedPath(node, path)
+	return path
+}
+
+// findFocusedPath recursively searches for the focused node
+// and marks all nodes on the path to it
+// Returns true if this node or any of its children is/contains the focused node
+func findFocusedPath(node *i3.Node, path map[i3.NodeID]bool) bool {
+	if node == nil {
+		return false
+	}
+
+	// Check if this node is focused
+	if node.Focused {
+		path[node.ID] = true
+		return true
+	}
+
+	// Check if any child contains the focused node (regular nodes)
+	for _, child := range node.Nodes {
+		if findFocusedPath(child, path) {
+			// This node is on the path to the focused node
+			path[node.ID] = true
+			return true
+		}
+	}
+
+	// Check floating nodes too
+	for _, child := range node.FloatingNodes {
+		if findFocusedPath(child, path) {
+			// This node is on the path to the focused node
+			path[node.ID] = true
+			return true
+		}
+	}
+
+	return false
+}
+
+// nodeTypeFormat returns the configured format for a node type
+// or nil if there's none
+func nodeTypeFormat(cfg *config.Config, nodeType i3.NodeType) *config.NodeFormat {
+	switch nodeType {
+	case "workspace":
+		return &cfg.Formatting.Workspace
+	case "con":
+		return &cfg.Formatting.Con
+	case "floating_con":
+		return &cfg.Formatting.FloatCon
+	case "output":
+		return &cfg.Formatting.Output
+	case "root":
+		return &cfg.Formatting.Root
+	default:
+		return nil
+	}
+}
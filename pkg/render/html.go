@@ -0,0 +1,193 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/njhoffman/i3-tree/pkg/config"
+	"go.i3wm.org/i3/v4"
+)
+
+// HTML renders the tree as a self contained page
+// Containers are collapsible <details> elements
+// and hovering a node shows its id, rect and marks
+type HTML struct {
+	w      io.Writer
+	config *config.Config
+}
+
+func NewHTML(w io.Writer) HTML {
+	return NewHTMLWithConfig(w, config.DefaultConfig())
+}
+
+func NewHTMLWithConfig(w io.Writer, cfg *config.Config) HTML {
+	return HTML{
+		w:      w,
+		config: cfg,
+	}
+}
+
+func (h HTML) Render(tree *i3.Tree) {
+	fmt.Fprint(h.w, `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>i3-tree</title>
+<style>
+`)
+	h.printStyle()
+	fmt.Fprint(h.w, `</style>
+</head>
+<body>
+`)
+
+	if tree.Root != nil {
+		h.print(tree.Root, buildFocusedPath(tree.Root), 0, false)
+	}
+
+	fmt.Fprint(h.w, `</body>
+</html>
+`)
+}
+
+// printStyle writes the stylesheet, colors come from the config
+func (h HTML) printStyle() {
+	f := h.config.Formatting
+
+	fmt.Fprint(h.w, `body { background: #1e1e1e; color: #e5e5e5; font-family: monospace; }
+.node { margin-left: 1.5em; padding-left: 0.5em; border-left: 1px solid #7f7f7f; }
+.node.depth-0 { margin-left: 0; border-left: none; }
+summary { cursor: pointer; }
+div.node { padding-left: calc(0.5em + 1ch); }
+`)
+
+	fmt.Fprintf(h.w, ".focused-path { border-left: 2px solid %s; }\n", hexOr(f.FocusBranches.ForegroundHex(), "currentColor"))
+	fmt.Fprintf(h.w, ".focused > summary, div.focused { %s }\n", cssStyle(f.FocusType))
+
+	rules := []struct {
+		selector string
+		format   config.NodeFormat
+	}{
+		{".type-root", f.Root},
+		{".type-output", f.Output},
+		{".type-workspace", f.Workspace},
+		{".type-con", f.Con},
+		{".type-floating_con", f.FloatCon},
+		{".layout", f.WindowLayout},
+		{".class", f.WindowClass},
+		{".focused .class", f.FocusClass},
+		{".title", f.WindowTitle},
+		{".marks", f.WindowMarks},
+	}
+	for _, r := range rules {
+		if style := cssStyle(r.format); style != "" {
+			fmt.Fprintf(h.w, "%s { %s }\n", r.selector, style)
+		}
+	}
+}
+
+func (h HTML) print(node *i3.Node, focusedPath map[i3.NodeID]bool, depth int, isFloating bool) {
+	isFloating = isFloating || node.Type == "floating_con"
+
+	classes := []string{"node", fmt.Sprintf("depth-%d", depth)}
+	if focusedPath[node.ID] {
+		classes = append(classes, "focused-path")
+	}
+	if node.Focused {
+		classes = append(classes, "focused")
+	}
+
+	attrs := fmt.Sprintf(`class="%s" title="%s"`, strings.Join(classes, " "), htmlTooltip(node))
+	label := h.label(node, isFloating)
+
+	// leaves have nothing to collapse
+	if len(node.Nodes) == 0 && len(node.FloatingNodes) == 0 {
+		fmt.Fprintf(h.w, "<div %s>%s</div>\n", attrs, label)
+		return
+	}
+
+	fmt.Fprintf(h.w, "<details open %s>\n<summary>%s</summary>\n", attrs, label)
+	for _, n := range node.Nodes {
+		h.print(n, focusedPath, depth+1, isFloating)
+	}
+	for _, n := range node.FloatingNodes {
+		h.print(n, focusedPath, depth+1, true)
+	}
+	fmt.Fprint(h.w, "</details>\n")
+}
+
+// label follows the console format: [type][layout] (class) title [marks]
+func (h HTML) label(node *i3.Node, isFloating bool) string {
+	nodeType := string(node.Type)
+	if isFloating && node.Type == "con" {
+		nodeType = "fcon"
+	}
+
+	s := fmt.Sprintf(`[<span class="type type-%s">%s</span>]`, html.EscapeString(string(node.Type)), html.EscapeString(nodeType))
+
+	if len(node.Nodes) > 0 && node.Layout != "" {
+		s += fmt.Sprintf(`[<span class="layout">%s</span>]`, html.EscapeString(string(node.Layout)))
+	}
+	if node.WindowProperties.Class != "" {
+		s += fmt.Sprintf(` <span class="class">(%s)</span>`, html.EscapeString(node.WindowProperties.Class))
+	}
+	if node.Name != "" {
+		s += fmt.Sprintf(` <span class="title">%s</span>`, html.EscapeString(node.Name))
+	}
+	if len(node.Marks) > 0 {
+		s += fmt.Sprintf(` <span class="marks">[%s]</span>`, html.EscapeString(strings.Join(node.Marks, ", ")))
+	}
+
+	return s
+}
+
+// htmlTooltip describes the node id, rect and marks, one per line
+func htmlTooltip(node *i3.Node) string {
+	lines := []string{
+		fmt.Sprintf("id: %d", node.ID),
+		fmt.Sprintf("rect: %dx%d+%d+%d", node.Rect.Width, node.Rect.Height, node.Rect.X, node.Rect.Y),
+	}
+	if len(node.Marks) > 0 {
+		lines = append(lines, "marks: "+strings.Join(node.Marks, ", "))
+	}
+
+	for i := range lines {
+		lines[i] = html.EscapeString(lines[i])
+	}
+	return strings.Join(lines, "&#10;")
+}
+
+// cssStyle converts a node format into CSS declarations
+func cssStyle(nf config.NodeFormat) string {
+	var decls []string
+
+	if color := nf.ForegroundHex(); color != "" {
+		decls = append(decls, "color: "+color+";")
+	}
+	if color := nf.BackgroundHex(); color != "" {
+		decls = append(decls, "background-color: "+color+";")
+	}
+	if nf.Attributes.Bold {
+		decls = append(decls, "font-weight: bold;")
+	}
+	if nf.Attributes.Italic {
+		decls = append(decls, "font-style: italic;")
+	}
+	if nf.Attributes.Underline {
+		decls = append(decls, "text-decoration: underline;")
+	}
+	if nf.Attributes.Dim {
+		decls = append(decls, "opacity: 0.6;")
+	}
+
+	return strings.Join(decls, " ")
+}
+
+func hexOr(color, fallback string) string {
+	if color == "" {
+		return fallback
+	}
+	return color
+}
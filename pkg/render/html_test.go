@@ -0,0 +1,91 @@
+package render_test
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/render"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.i3wm.org/i3/v4"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// assertGolden compares got with testdata/name
+// run with -update to write got instead
+func assertGolden(t *testing.T, name string, got []byte) {
+	path := filepath.Join("testdata", name)
+
+	if *update {
+		require.NoError(t, os.MkdirAll("testdata", 0755))
+		require.NoError(t, ioutil.WriteFile(path, got, 0644))
+	}
+
+	want, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+func TestHTMLRenderer(t *testing.T) {
+	tree := i3.Tree{
+		Root: &i3.Node{
+			ID:     1,
+			Name:   "root",
+			Type:   i3.NodeType(i3.Root),
+			Layout: i3.Layout(i3.SplitH),
+			Rect:   i3.Rect{Width: 1920, Height: 1080},
+			Nodes: []*i3.Node{
+				{
+					ID:     2,
+					Name:   "1",
+					Type:   i3.NodeType(i3.WorkspaceNode),
+					Layout: i3.Layout(i3.SplitH),
+					Rect:   i3.Rect{Y: 24, Width: 1920, Height: 1056},
+					Nodes: []*i3.Node{
+						{
+							ID:      3,
+							Name:    "<script>alert(1)</script>",
+							Type:    i3.NodeType(i3.Con),
+							Focused: true,
+							Marks:   []string{"_last", "a&b"},
+							Rect:    i3.Rect{Y: 24, Width: 960, Height: 1056},
+							WindowProperties: i3.WindowProperties{
+								Class: "Alacritty",
+							},
+						},
+						{
+							ID:   4,
+							Name: "Slack",
+							Type: i3.NodeType(i3.Con),
+							Rect: i3.Rect{X: 960, Y: 24, Width: 960, Height: 1056},
+						},
+					},
+					FloatingNodes: []*i3.Node{
+						{
+							ID:   5,
+							Type: "floating_con",
+							Nodes: []*i3.Node{
+								{ID: 6, Name: "mpv", Type: i3.NodeType(i3.Con)},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var writer bytes.Buffer
+	r := render.NewHTML(io.Writer(&writer))
+	r.Render(&tree)
+
+	got := writer.Bytes()
+	assert.NotContains(t, string(got), "<script>")
+	assert.NotContains(t, string(got), "http")
+	assertGolden(t, "tree.html", got)
+}
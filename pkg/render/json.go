@@ -0,0 +1,124 @@
+package render
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"go.i3wm.org/i3/v4"
+)
+
+// JSON renders the tree as JSON, meant to be consumed by scripts
+// The schema is JSONNode, the root node being the top level value
+// An empty tree is rendered as null
+type JSON struct {
+	w      io.Writer
+	indent bool
+
+	// Extras fill in the fields go-i3 doesn't decode, when set
+	Extras fetch.Extras
+}
+
+// JSONNode is the schema of every node rendered by JSON
+// Fields are always present, so consumers don't need to check for them
+type JSONNode struct {
+	// i3's container id, usable as [con_id=...] criteria
+	ID int64 `json:"id"`
+	// root, output, con, floating_con, workspace or dockarea
+	Type string `json:"type"`
+	// splith, splitv, stacked, tabbed, dockarea or output
+	Layout string `json:"layout"`
+	// window title for windows, internal name for other containers
+	Name     string   `json:"name"`
+	Class    string   `json:"class"`
+	Instance string   `json:"instance"`
+	Marks    []string `json:"marks"`
+
+	Focused bool `json:"focused"`
+	Urgent  bool `json:"urgent"`
+	// true for floating containers and everything within them
+	Floating bool `json:"floating"`
+	// only ever true for windows
+	Fullscreen bool `json:"fullscreen"`
+
+	// sway only: process id of a window, 0 otherwise
+	PID int `json:"pid"`
+	// sway only: whether a window or workspace is shown, null otherwise
+	Visible *bool `json:"visible"`
+	// sway only: whether a window keeps the outputs from idling
+	InhibitIdle bool `json:"inhibit_idle"`
+	// fresh or changed for containers sent to the scratchpad, none otherwise, empty when unknown
+	ScratchpadState string `json:"scratchpad_state"`
+
+	// tiling children first, followed by floating ones
+	Children []*JSONNode `json:"children"`
+}
+
+func NewJSON(w io.Writer) JSON {
+	return JSON{w: w, indent: true}
+}
+
+func NewCompactJSON(w io.Writer) JSON {
+	return JSON{w: w, indent: false}
+}
+
+func (j JSON) Render(tree *i3.Tree) {
+	if err := j.TryRender(tree); err != nil {
+		log.Print(err)
+	}
+}
+
+// TryRender fails when the JSON can't be written
+func (j JSON) TryRender(tree *i3.Tree) error {
+	enc := json.NewEncoder(j.w)
+	if j.indent {
+		enc.SetIndent("", "  ")
+	}
+
+	return enc.Encode(newJSONNode(tree.Root, false, j.Extras))
+}
+
+// newJSONNode converts a i3 node and all its children into a JSONNode
+func newJSONNode(node *i3.Node, isFloating bool, extras fetch.Extras) *JSONNode {
+	if node == nil {
+		return nil
+	}
+
+	isFloating = isFloating || node.Type == "floating_con"
+	extra := extras[node.ID]
+
+	marks := node.Marks
+	if marks == nil {
+		marks = []string{}
+	}
+
+	n := &JSONNode{
+		ID:       int64(node.ID),
+		Type:     string(node.Type),
+		Layout:   string(node.Layout),
+		Name:     node.Name,
+		Class:    node.WindowProperties.Class,
+		Instance: node.WindowProperties.Instance,
+		Marks:    marks,
+		Focused:  node.Focused,
+		Urgent:   node.Urgent,
+		Floating: isFloating,
+		// i3 reports workspaces as fullscreen, only windows can really be
+		Fullscreen:      node.FullscreenMode != 0 && node.Type == "con",
+		PID:             extra.PID,
+		Visible:         extra.Visible,
+		InhibitIdle:     extra.InhibitIdle,
+		ScratchpadState: extra.ScratchpadState,
+		Children:        make([]*JSONNode, 0, len(node.Nodes)+len(node.FloatingNodes)),
+	}
+
+	for _, c := range node.Nodes {
+		n.Children = append(n.Children, newJSONNode(c, isFloating, extras))
+	}
+	for _, c := range node.FloatingNodes {
+		n.Children = append(n.Children, newJSONNode(c, true, extras))
+	}
+
+	return n
+}
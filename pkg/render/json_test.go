@@ -0,0 +1,212 @@
+package render_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/njhoffman/i3-tree/pkg/render"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.i3wm.org/i3/v4"
+)
+
+func TestJSONRenderer(t *testing.T) {
+	tree := i3.Tree{
+		Root: &i3.Node{
+			ID:     1,
+			Name:   "root",
+			Type:   i3.NodeType(i3.Root),
+			Layout: i3.Layout(i3.SplitH),
+			Nodes: []*i3.Node{
+				{
+					ID:     2,
+					Name:   "1",
+					Type:   i3.NodeType(i3.WorkspaceNode),
+					Layout: i3.Layout(i3.Tabbed),
+					// i3 reports workspaces as fullscreen
+					FullscreenMode: 1,
+					Nodes: []*i3.Node{
+						{
+							ID:      3,
+							Name:    "main",
+							Type:    i3.NodeType(i3.Con),
+							Layout:  i3.Layout(i3.SplitH),
+							Focused: true,
+							Marks:   []string{"_last"},
+							WindowProperties: i3.WindowProperties{
+								Class:    "Alacritty",
+								Instance: "Alacritty",
+							},
+						},
+					},
+					FloatingNodes: []*i3.Node{
+						{
+							ID:   4,
+							Type: "floating_con",
+							Nodes: []*i3.Node{
+								{
+									ID:             5,
+									Name:           "mpv",
+									Type:           i3.NodeType(i3.Con),
+									Urgent:         true,
+									FullscreenMode: 1,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	want := `{
+  "id": 1,
+  "type": "root",
+  "layout": "splith",
+  "name": "root",
+  "class": "",
+  "instance": "",
+  "marks": [],
+  "focused": false,
+  "urgent": false,
+  "floating": false,
+  "fullscreen": false,
+  "pid": 0,
+  "visible": null,
+  "inhibit_idle": false,
+  "scratchpad_state": "",
+  "children": [
+    {
+      "id": 2,
+      "type": "workspace",
+      "layout": "tabbed",
+      "name": "1",
+      "class": "",
+      "instance": "",
+      "marks": [],
+      "focused": false,
+      "urgent": false,
+      "floating": false,
+      "fullscreen": false,
+      "pid": 0,
+      "visible": null,
+      "inhibit_idle": false,
+      "scratchpad_state": "",
+      "children": [
+        {
+          "id": 3,
+          "type": "con",
+          "layout": "splith",
+          "name": "main",
+          "class": "Alacritty",
+          "instance": "Alacritty",
+          "marks": [
+            "_last"
+          ],
+          "focused": true,
+          "urgent": false,
+          "floating": false,
+          "fullscreen": false,
+          "pid": 4242,
+          "visible": true,
+          "inhibit_idle": true,
+          "scratchpad_state": "",
+          "children": []
+        },
+        {
+          "id": 4,
+          "type": "floating_con",
+          "layout": "",
+          "name": "",
+          "class": "",
+          "instance": "",
+          "marks": [],
+          "focused": false,
+          "urgent": false,
+          "floating": true,
+          "fullscreen": false,
+          "pid": 0,
+          "visible": null,
+          "inhibit_idle": false,
+          "scratchpad_state": "fresh",
+          "children": [
+            {
+              "id": 5,
+              "type": "con",
+              "layout": "",
+              "name": "mpv",
+              "class": "",
+              "instance": "",
+              "marks": [],
+              "focused": false,
+              "urgent": true,
+              "floating": true,
+              "fullscreen": true,
+              "pid": 0,
+              "visible": null,
+              "inhibit_idle": false,
+              "scratchpad_state": "",
+              "children": []
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`
+
+	visible := true
+	var writer bytes.Buffer
+	r := render.NewJSON(io.Writer(&writer))
+	r.Extras = fetch.Extras{
+		3: {PID: 4242, Visible: &visible, InhibitIdle: true},
+		4: {ScratchpadState: "fresh"},
+	}
+	r.Render(&tree)
+
+	assert.Equal(t, want, writer.String())
+}
+
+func TestJSONRendererCompact(t *testing.T) {
+	tree := fetch.FakeTree()
+
+	var writer bytes.Buffer
+	r := render.NewCompactJSON(io.Writer(&writer))
+	r.Render(tree)
+
+	got := writer.String()
+	assert.Equal(t, 1, bytes.Count(writer.Bytes(), []byte("\n")))
+
+	var root render.JSONNode
+	require.NoError(t, json.Unmarshal([]byte(got), &root))
+	assert.Equal(t, "root", root.Type)
+	assert.Len(t, root.Children[0].Children, 5)
+	assert.Equal(t, "Slack", root.Children[0].Children[3].Children[2].Name)
+}
+
+func TestJSONRendererEmptyTree(t *testing.T) {
+	var writer bytes.Buffer
+	r := render.NewCompactJSON(io.Writer(&writer))
+	r.Render(&i3.Tree{})
+
+	assert.Equal(t, "null\n", writer.String())
+}
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestJSONRendererWriteError(t *testing.T) {
+	tree := fetch.FakeTree()
+	r := render.NewJSON(failingWriter{})
+
+	assert.EqualError(t, r.TryRender(tree), "disk full")
+}
@@ -0,0 +1,437 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/njhoffman/i3-tree/pkg/config"
+	"go.i3wm.org/i3/v4"
+)
+
+// DefaultMapWidth is the number of columns a workspace is scaled to
+const DefaultMapWidth = 80
+
+// Map draws what every workspace looks like on screen,
+// scaling the rect of each container into a box
+// Tabbed and stacked containers show their tabs instead of their children
+type Map struct {
+	w      io.Writer
+	config *config.Config
+	Width  int
+}
+
+func NewMap(w io.Writer) Map {
+	return NewMapWithConfig(w, config.DefaultConfig())
+}
+
+func NewMapWithConfig(w io.Writer, cfg *config.Config) Map {
+	return Map{
+		w:      w,
+		config: cfg,
+		Width:  DefaultMapWidth,
+	}
+}
+
+func (m Map) Render(tree *i3.Tree) {
+	focusedPath := buildFocusedPath(tree.Root)
+	m.walk(tree.Root, "", focusedPath)
+}
+
+// walk draws every workspace found, remembering which output it's on
+func (m Map) walk(node *i3.Node, output string, focusedPath map[i3.NodeID]bool) {
+	if node == nil {
+		return
+	}
+
+	switch node.Type {
+	case "output":
+		output = node.Name
+	case "workspace":
+		m.printWorkspace(node, output, focusedPath)
+		return
+	}
+
+	for _, n := range node.Nodes {
+		m.walk(n, output, focusedPath)
+	}
+}
+
+func (m Map) printWorkspace(ws *i3.Node, output string, focusedPath map[i3.NodeID]bool) {
+	if ws.Rect.Width <= 0 || ws.Rect.Height <= 0 {
+		return
+	}
+
+	heading := fmt.Sprintf("workspace %s", ws.Name)
+	if output != "" {
+		heading += " on " + output
+	}
+	fmt.Fprintf(m.w, "%s (%dx%d)\n", heading, ws.Rect.Width, ws.Rect.Height)
+
+	// terminal cells are about twice as tall as they are wide
+	scale := float64(m.Width) / float64(ws.Rect.Width)
+	rows := int(math.Round(float64(ws.Rect.Height) * scale / 2))
+	if rows < 2 {
+		rows = 2
+	}
+
+	c := newMapCanvas(ws.Rect, scale, m.Width, rows)
+
+	c.box(ws.Rect, false)
+	c.container(ws, focusedPath)
+	for _, n := range ws.FloatingNodes {
+		c.container(n, focusedPath)
+	}
+
+	fmt.Fprint(m.w, c.String())
+}
+
+// directions a box drawing line leaves a cell towards
+const (
+	lineUp = 1 << iota
+	lineDown
+	lineLeft
+	lineRight
+)
+
+var lightLines = map[uint8]rune{
+	lineUp: '│', lineDown: '│', lineUp | lineDown: '│',
+	lineLeft: '─', lineRight: '─', lineLeft | lineRight: '─',
+	lineDown | lineRight: '┌', lineDown | lineLeft: '┐',
+	lineUp | lineRight: '└', lineUp | lineLeft: '┘',
+	lineUp | lineDown | lineRight: '├', lineUp | lineDown | lineLeft: '┤',
+	lineDown | lineLeft | lineRight: '┬', lineUp | lineLeft | lineRight: '┴',
+	lineUp | lineDown | lineLeft | lineRight: '┼',
+}
+
+var heavyLines = map[uint8]rune{
+	lineUp: '┃', lineDown: '┃', lineUp | lineDown: '┃',
+	lineLeft: '━', lineRight: '━', lineLeft | lineRight: '━',
+	lineDown | lineRight: '┏', lineDown | lineLeft: '┓',
+	lineUp | lineRight: '┗', lineUp | lineLeft: '┛',
+	lineUp | lineDown | lineRight: '┣', lineUp | lineDown | lineLeft: '┫',
+	lineDown | lineLeft | lineRight: '┳', lineUp | lineLeft | lineRight: '┻',
+	lineUp | lineDown | lineLeft | lineRight: '╋',
+}
+
+// mapCanvas is a grid of cells the boxes are drawn into
+// borders that touch are merged into junctions
+type mapCanvas struct {
+	origin i3.Rect
+	scale  float64
+	cols   int
+	rows   int
+
+	lines [][]uint8
+	heavy [][]bool
+	text  [][]rune
+}
+
+func newMapCanvas(origin i3.Rect, scale float64, cols, rows int) *mapCanvas {
+	c := &mapCanvas{
+		origin: origin,
+		scale:  scale,
+		cols:   cols,
+		rows:   rows,
+	}
+
+	// boxes go from 0 to cols/rows included
+	for y := 0; y <= rows; y++ {
+		c.lines = append(c.lines, make([]uint8, cols+1))
+		c.heavy = append(c.heavy, make([]bool, cols+1))
+		c.text = append(c.text, make([]rune, cols+1))
+	}
+
+	return c
+}
+
+// cells converts a rect into the corners of a box on the canvas
+func (c *mapCanvas) cells(r i3.Rect) (int, int, int, int) {
+	conv := func(v, origin int64, scale float64, max int) int {
+		cell := int(math.Round(float64(v-origin) * scale))
+		if cell < 0 {
+			return 0
+		}
+		if cell > max {
+			return max
+		}
+		return cell
+	}
+
+	x0 := conv(r.X, c.origin.X, c.scale, c.cols)
+	x1 := conv(r.X+r.Width, c.origin.X, c.scale, c.cols)
+	y0 := conv(r.Y, c.origin.Y, c.scale/2, c.rows)
+	y1 := conv(r.Y+r.Height, c.origin.Y, c.scale/2, c.rows)
+
+	return x0, y0, x1, y1
+}
+
+// container draws a node and the children it shows
+func (c *mapCanvas) container(node *i3.Node, focusedPath map[i3.NodeID]bool) {
+	// floating containers wrap a single window
+	if node.Type == "floating_con" && len(node.Nodes) == 1 {
+		child := node.Nodes[0]
+		c.window(node.Rect, child, child.Focused)
+		return
+	}
+
+	if len(node.Nodes) == 0 {
+		if node.Type != "workspace" {
+			c.window(node.Rect, node, node.Focused)
+		}
+		return
+	}
+
+	switch node.Layout {
+	case i3.Layout(i3.Tabbed), i3.Layout(i3.Stacked):
+		c.tabs(node, focusedPath)
+	default:
+		for _, n := range node.Nodes {
+			c.container(n, focusedPath)
+		}
+	}
+
+	// focused windows within are drawn on top of their siblings
+	for _, n := range node.Nodes {
+		if n.Focused && len(n.Nodes) == 0 && node.Layout != i3.Layout(i3.Tabbed) && node.Layout != i3.Layout(i3.Stacked) {
+			c.window(n.Rect, n, true)
+		}
+	}
+}
+
+// tabs draws a tabbed or stacked container as a box
+// listing its tabs and the content of the active one
+func (c *mapCanvas) tabs(node *i3.Node, focusedPath map[i3.NodeID]bool) {
+	active := activeChild(node, focusedPath)
+
+	x0, y0, x1, y1 := c.box(node.Rect, active.Focused)
+
+	labels := make([]string, 0, len(node.Nodes))
+	for _, n := range node.Nodes {
+		label := tabLabel(n)
+		if n == active {
+			label = "[" + label + "]"
+		}
+		if n.Focused {
+			label = "*" + label
+		}
+		labels = append(labels, label)
+	}
+
+	row := y0 + 1
+	if node.Layout == i3.Layout(i3.Tabbed) {
+		c.tabRow(node, labels, x0, row, x1)
+		row++
+	} else {
+		for _, l := range labels {
+			if row >= y1 {
+				break
+			}
+			c.write(x0+1, row, x1, l)
+			row++
+		}
+	}
+
+	// a line between the tabs and the content
+	if row < y1 {
+		for x := x0 + 1; x < x1; x++ {
+			c.text[row][x] = '╌'
+		}
+		row++
+	}
+
+	for _, line := range windowLabel(active) {
+		if row >= y1 {
+			break
+		}
+		c.write(x0+1, row, x1, line)
+		row++
+	}
+}
+
+// tabRow writes the tabs of a tabbed container on row y
+// every tab is put where its title is drawn on screen, given by its deco_rect,
+// or they are listed one after the other when it isn't known
+func (c *mapCanvas) tabRow(node *i3.Node, labels []string, x0, y, x1 int) {
+	for _, n := range node.Nodes {
+		if n.DecoRect.Width <= 0 {
+			c.write(x0+1, y, x1, strings.Join(labels, " │ "))
+			return
+		}
+	}
+
+	for i, n := range node.Nodes {
+		// deco_rect is relative to the container
+		r := n.DecoRect
+		r.X += node.Rect.X
+		r.Y += node.Rect.Y
+
+		start, _, end, _ := c.cells(r)
+		if start < x0 {
+			start = x0
+		}
+		if end > x1 || i == len(node.Nodes)-1 {
+			end = x1
+		}
+		if i > 0 && start > x0 && start < x1 {
+			c.text[y][start] = '│'
+		}
+		c.write(start+1, y, end, labels[i])
+	}
+}
+
+// window draws a box labelled with the window class and title
+func (c *mapCanvas) window(r i3.Rect, node *i3.Node, focused bool) {
+	x0, y0, x1, y1 := c.box(r, focused)
+
+	row := y0 + 1
+	for _, line := range windowLabel(node) {
+		if row >= y1 {
+			break
+		}
+		if focused && row == y0+1 {
+			line = "* " + line
+		}
+		c.write(x0+1, row, x1, line)
+		row++
+	}
+}
+
+// box draws the borders of a rect, returning the cells it spans
+// it also clears what was drawn inside, so boxes drawn later are on top
+func (c *mapCanvas) box(r i3.Rect, heavy bool) (int, int, int, int) {
+	x0, y0, x1, y1 := c.cells(r)
+	if x1-x0 < 1 || y1-y0 < 1 {
+		return x0, y0, x1, y1
+	}
+
+	for y := y0 + 1; y < y1; y++ {
+		for x := x0 + 1; x < x1; x++ {
+			c.lines[y][x] = 0
+			c.heavy[y][x] = false
+			c.text[y][x] = 0
+		}
+	}
+
+	set := func(x, y int, dirs uint8) {
+		c.lines[y][x] |= dirs
+		c.text[y][x] = 0
+		if heavy {
+			c.heavy[y][x] = true
+		}
+	}
+
+	for x := x0; x <= x1; x++ {
+		var dirs uint8
+		if x > x0 {
+			dirs |= lineLeft
+		}
+		if x < x1 {
+			dirs |= lineRight
+		}
+		set(x, y0, dirs)
+		set(x, y1, dirs)
+	}
+	for y := y0; y <= y1; y++ {
+		var dirs uint8
+		if y > y0 {
+			dirs |= lineUp
+		}
+		if y < y1 {
+			dirs |= lineDown
+		}
+		set(x0, y, dirs)
+		set(x1, y, dirs)
+	}
+
+	return x0, y0, x1, y1
+}
+
+// write puts s on row y from x, truncating it before column end
+func (c *mapCanvas) write(x, y, end int, s string) {
+	if y < 0 || y > c.rows {
+		return
+	}
+
+	for _, r := range s {
+		if x >= end {
+			return
+		}
+		c.text[y][x] = r
+		c.lines[y][x] = 0
+		x++
+	}
+}
+
+func (c *mapCanvas) String() string {
+	var sb strings.Builder
+
+	for y := range c.text {
+		line := make([]rune, 0, len(c.text[y]))
+		for x, r := range c.text[y] {
+			switch {
+			case r != 0:
+				line = append(line, r)
+			case c.lines[y][x] != 0 && c.heavy[y][x]:
+				line = append(line, heavyLines[c.lines[y][x]])
+			case c.lines[y][x] != 0:
+				line = append(line, lightLines[c.lines[y][x]])
+			default:
+				line = append(line, ' ')
+			}
+		}
+		sb.WriteString(strings.TrimRight(string(line), " "))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// activeChild is the tab that is shown: the one leading to the focused window,
+// otherwise the one i3 focused last, otherwise the first one
+func activeChild(node *i3.Node, focusedPath map[i3.NodeID]bool) *i3.Node {
+	for _, n := range node.Nodes {
+		if focusedPath[n.ID] && n.ID != 0 || n.Focused {
+			return n
+		}
+	}
+
+	if len(node.Focus) > 0 {
+		for _, n := range node.Nodes {
+			if n.ID == node.Focus[0] {
+				return n
+			}
+		}
+	}
+
+	return node.Nodes[0]
+}
+
+// tabLabel is the short name of a tab
+func tabLabel(node *i3.Node) string {
+	if node.WindowProperties.Class != "" {
+		return node.WindowProperties.Class
+	}
+	if node.Name != "" {
+		return node.Name
+	}
+	return "[" + string(node.Layout) + "]"
+}
+
+// windowLabel is the class and title of a window, one per line
+func windowLabel(node *i3.Node) []string {
+	var lines []string
+
+	if node.WindowProperties.Class != "" {
+		lines = append(lines, "("+node.WindowProperties.Class+")")
+	}
+	if node.Name != "" {
+		lines = append(lines, node.Name)
+	}
+	if len(lines) == 0 && len(node.Nodes) > 0 {
+		lines = append(lines, fmt.Sprintf("[%s] %d windows", node.Layout, len(node.Nodes)))
+	}
+
+	return lines
+}
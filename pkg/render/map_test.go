@@ -0,0 +1,122 @@
+package render_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/njhoffman/i3-tree/pkg/render"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.i3wm.org/i3/v4"
+)
+
+func TestMapRendererMock(t *testing.T) {
+	tree, err := fetch.FromFake{}.Fetch()
+	require.NoError(t, err)
+
+	var writer bytes.Buffer
+	r := render.NewMap(io.Writer(&writer))
+	r.Render(&tree)
+
+	assertGolden(t, "tree.map", writer.Bytes())
+}
+
+func TestMapRenderer(t *testing.T) {
+	tree := i3.Tree{
+		Root: &i3.Node{
+			ID:   1,
+			Type: i3.NodeType(i3.Root),
+			Nodes: []*i3.Node{
+				{
+					ID:   2,
+					Name: "DP-2",
+					Type: i3.NodeType(i3.OutputNode),
+					Nodes: []*i3.Node{
+						{
+							ID:     3,
+							Name:   "3:mail",
+							Type:   i3.NodeType(i3.WorkspaceNode),
+							Layout: i3.Layout(i3.SplitH),
+							Rect:   i3.Rect{Width: 400, Height: 200},
+							Nodes: []*i3.Node{
+								{
+									ID:     4,
+									Type:   i3.NodeType(i3.Con),
+									Layout: i3.Layout(i3.Tabbed),
+									Rect:   i3.Rect{Width: 200, Height: 200},
+									Focus:  []i3.NodeID{6, 5},
+									Nodes: []*i3.Node{
+										{
+											ID:               5,
+											Name:             "Inbox",
+											Type:             i3.NodeType(i3.Con),
+											Rect:             i3.Rect{Y: 20, Width: 200, Height: 180},
+											WindowProperties: i3.WindowProperties{Class: "thunderbird"},
+										},
+										{
+											ID:               6,
+											Name:             "#general",
+											Type:             i3.NodeType(i3.Con),
+											Rect:             i3.Rect{Y: 20, Width: 200, Height: 180},
+											WindowProperties: i3.WindowProperties{Class: "Slack"},
+										},
+									},
+								},
+								{
+									ID:               7,
+									Name:             "vim",
+									Type:             i3.NodeType(i3.Con),
+									Focused:          true,
+									Rect:             i3.Rect{X: 200, Width: 200, Height: 200},
+									WindowProperties: i3.WindowProperties{Class: "Alacritty"},
+								},
+							},
+							FloatingNodes: []*i3.Node{
+								{
+									ID:   8,
+									Type: i3.NodeType(i3.FloatingCon),
+									Rect: i3.Rect{X: 100, Y: 100, Width: 200, Height: 60},
+									Nodes: []*i3.Node{
+										{
+											ID:               9,
+											Name:             "Passwords",
+											Type:             i3.NodeType(i3.Con),
+											Rect:             i3.Rect{X: 100, Y: 100, Width: 200, Height: 60},
+											WindowProperties: i3.WindowProperties{Class: "KeePassXC"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var writer bytes.Buffer
+	r := render.NewMap(io.Writer(&writer))
+	r.Width = 40
+	r.Render(&tree)
+
+	want := strings.Join([]string{
+		"workspace 3:mail on DP-2 (400x200)",
+		"┌───────────────────┳━━━━━━━━━━━━━━━━━━━┓",
+		"│thunderbird │ [Slac┃* (Alacritty)      ┃",
+		"│╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌┃vim                ┃",
+		"│(Slack)            ┃                   ┃",
+		"│#general           ┃                   ┃",
+		"│         ┌─────────╋─────────┐         ┃",
+		"│         │(KeePassXC)        │         ┃",
+		"│         │Passwords          │         ┃",
+		"│         └─────────╋─────────┘         ┃",
+		"│                   ┃                   ┃",
+		"└───────────────────┻━━━━━━━━━━━━━━━━━━━┛",
+		"",
+	}, "\n")
+
+	assert.Equal(t, want, writer.String())
+}
@@ -0,0 +1,218 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/njhoffman/i3-tree/pkg/config"
+	"go.i3wm.org/i3/v4"
+)
+
+// Mermaid renders the tree as a Mermaid graph, to be embedded in Markdown
+type Mermaid struct {
+	w      io.Writer
+	config *config.Config
+}
+
+// mermaidShapes maps every node type to the brackets of a Mermaid node shape
+var mermaidShapes = map[i3.NodeType][2]string{
+	"root":         {"((", "))"},
+	"output":       {"[[", "]]"},
+	"workspace":    {"{{", "}}"},
+	"dockarea":     {"[(", ")]"},
+	"con":          {"[", "]"},
+	"floating_con": {"(", ")"},
+}
+
+// mermaidState is what is accumulated while printing a graph
+type mermaidState struct {
+	focusedPath map[i3.NodeID]bool
+	// ids already given out, to keep them unique
+	ids map[string]bool
+	// edges are numbered in the order they are printed
+	edges        int
+	focusedEdges []string
+	// graph id of every node grouped by class
+	classes map[string][]string
+}
+
+func NewMermaid(w io.Writer) Mermaid {
+	return NewMermaidWithConfig(w, config.DefaultConfig())
+}
+
+func NewMermaidWithConfig(w io.Writer, cfg *config.Config) Mermaid {
+	return Mermaid{
+		w:      w,
+		config: cfg,
+	}
+}
+
+func (m Mermaid) Render(tree *i3.Tree) {
+	st := &mermaidState{
+		focusedPath: buildFocusedPath(tree.Root),
+		ids:         make(map[string]bool),
+		classes:     make(map[string][]string),
+	}
+
+	fmt.Fprintln(m.w, "graph TD")
+
+	if tree.Root != nil {
+		m.print(tree.Root, "", "", st, false)
+	}
+
+	m.printStyles(st)
+}
+
+func (m Mermaid) print(node *i3.Node, parent string, layout i3.Layout, st *mermaidState, isFloating bool) {
+	id := st.nodeID(node)
+	isFloating = isFloating || node.Type == "floating_con"
+
+	shape, ok := mermaidShapes[node.Type]
+	if !ok {
+		shape = mermaidShapes["con"]
+	}
+	fmt.Fprintf(m.w, "  %s%s\"%s\"%s\n", id, shape[0], mermaidEscape(mermaidLabel(node, isFloating)), shape[1])
+
+	if parent != "" {
+		if layout != "" {
+			fmt.Fprintf(m.w, "  %s -->|%s| %s\n", parent, mermaidEscape(string(layout)), id)
+		} else {
+			fmt.Fprintf(m.w, "  %s --> %s\n", parent, id)
+		}
+
+		if st.focusedPath[node.ID] {
+			st.focusedEdges = append(st.focusedEdges, strconv.Itoa(st.edges))
+		}
+		st.edges++
+	}
+
+	if nodeTypeFormat(m.config, node.Type) != nil {
+		class := mermaidClass(node.Type)
+		st.classes[class] = append(st.classes[class], id)
+	}
+	if node.Focused {
+		st.classes["focused"] = append(st.classes["focused"], id)
+	}
+
+	for _, n := range node.Nodes {
+		m.print(n, id, node.Layout, st, isFloating)
+	}
+	// floating windows are not arranged by the layout
+	for _, n := range node.FloatingNodes {
+		m.print(n, id, "", st, true)
+	}
+}
+
+// printStyles assigns the configured colors to nodes and the focused path
+func (m Mermaid) printStyles(st *mermaidState) {
+	for _, nodeType := range []i3.NodeType{"root", "output", "workspace", "con", "floating_con"} {
+		class := mermaidClass(nodeType)
+		ids, ok := st.classes[class]
+		if !ok {
+			continue
+		}
+
+		// nothing to style with the terminal default colors
+		def := mermaidClassDef(*nodeTypeFormat(m.config, nodeType), "")
+		if def == "" {
+			continue
+		}
+
+		fmt.Fprintf(m.w, "  classDef %s %s\n", class, def)
+		fmt.Fprintf(m.w, "  class %s %s\n", strings.Join(ids, ","), class)
+	}
+
+	if ids, ok := st.classes["focused"]; ok {
+		def := mermaidClassDef(m.config.Formatting.FocusClass, "stroke-width:3px")
+		fmt.Fprintf(m.w, "  classDef focused %s\n", def)
+		fmt.Fprintf(m.w, "  class %s focused\n", strings.Join(ids, ","))
+	}
+
+	if len(st.focusedEdges) > 0 {
+		style := "stroke-width:2px"
+		if color := m.config.Formatting.FocusBranches.ForegroundHex(); color != "" {
+			style = "stroke:" + color + "," + style
+		}
+		fmt.Fprintf(m.w, "  linkStyle %s %s\n", strings.Join(st.focusedEdges, ","), style)
+	}
+}
+
+// nodeID derives a graph id from the i3 id
+// suffixing it when it's been used already (e.g. mock data has no ids)
+func (st *mermaidState) nodeID(node *i3.Node) string {
+	base := fmt.Sprintf("con%d", node.ID)
+
+	id := base
+	for i := 1; st.ids[id]; i++ {
+		id = fmt.Sprintf("%s_%d", base, i)
+	}
+	st.ids[id] = true
+
+	return id
+}
+
+// mermaidClass is the class name of a node type
+func mermaidClass(nodeType i3.NodeType) string {
+	return "type_" + string(nodeType)
+}
+
+// mermaidClassDef converts a node format into a Mermaid style
+func mermaidClassDef(nf config.NodeFormat, extra string) string {
+	var styles []string
+
+	if color := nf.ForegroundHex(); color != "" {
+		styles = append(styles, "color:"+color, "stroke:"+color)
+	}
+	if color := nf.BackgroundHex(); color != "" {
+		styles = append(styles, "fill:"+color)
+	}
+	if nf.Attributes.Bold {
+		styles = append(styles, "font-weight:bold")
+	}
+	if nf.Attributes.Italic {
+		styles = append(styles, "font-style:italic")
+	}
+	if nf.Attributes.Underline {
+		styles = append(styles, "text-decoration:underline")
+	}
+	if extra != "" {
+		styles = append(styles, extra)
+	}
+
+	return strings.Join(styles, ",")
+}
+
+// mermaidLabel describes a node as [type] (class) name
+func mermaidLabel(node *i3.Node, isFloating bool) string {
+	nodeType := string(node.Type)
+	if isFloating && node.Type == "con" {
+		nodeType = "fcon"
+	}
+
+	label := "[" + nodeType + "]"
+	if node.WindowProperties.Class != "" {
+		label += " (" + node.WindowProperties.Class + ")"
+	}
+	if node.Name != "" {
+		label += " " + node.Name
+	}
+
+	return label
+}
+
+// mermaidEscaper replaces the characters Mermaid could interpret with entity codes
+var mermaidEscaper = strings.NewReplacer(
+	"#", "#35;",
+	`"`, "#quot;",
+	"<", "#lt;",
+	">", "#gt;",
+	"|", "#124;",
+	"\n", " ",
+)
+
+// mermaidEscape makes any text safe to be used within a quoted label
+func mermaidEscape(s string) string {
+	return mermaidEscaper.Replace(s)
+}
@@ -0,0 +1,110 @@
+package render_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/njhoffman/i3-tree/pkg/render"
+	"github.com/stretchr/testify/assert"
+	"go.i3wm.org/i3/v4"
+)
+
+func TestMermaidRenderer(t *testing.T) {
+	tree := i3.Tree{
+		Root: &i3.Node{
+			ID:     1,
+			Name:   "root",
+			Type:   i3.NodeType(i3.Root),
+			Layout: i3.Layout(i3.SplitH),
+			Nodes: []*i3.Node{
+				{
+					ID:     2,
+					Name:   "1",
+					Type:   i3.NodeType(i3.WorkspaceNode),
+					Layout: i3.Layout(i3.SplitV),
+					Nodes: []*i3.Node{
+						{
+							ID:      3,
+							Name:    `<b>"#1" | issues</b>`,
+							Type:    i3.NodeType(i3.Con),
+							Focused: true,
+							WindowProperties: i3.WindowProperties{
+								Class: "firefox",
+							},
+						},
+						{
+							ID:   4,
+							Name: "Slack",
+							Type: i3.NodeType(i3.Con),
+						},
+					},
+					FloatingNodes: []*i3.Node{
+						{
+							ID:   5,
+							Type: "floating_con",
+							Nodes: []*i3.Node{
+								{ID: 6, Name: "mpv", Type: i3.NodeType(i3.Con)},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	want := `graph TD
+  con1(("[root] root"))
+  con2{{"[workspace] 1"}}
+  con1 -->|splith| con2
+  con3["[con] (firefox) #lt;b#gt;#quot;#35;1#quot; #124; issues#lt;/b#gt;"]
+  con2 -->|splitv| con3
+  con4["[con] Slack"]
+  con2 -->|splitv| con4
+  con5("[floating_con]")
+  con2 --> con5
+  con6["[fcon] mpv"]
+  con5 --> con6
+  classDef type_workspace color:#00cdcd,stroke:#00cdcd
+  class con2 type_workspace
+  classDef type_con color:#0000ee,stroke:#0000ee
+  class con3,con4,con6 type_con
+  classDef type_floating_con color:#0000ee,stroke:#0000ee
+  class con5 type_floating_con
+  classDef focused color:#e4e4e4,stroke:#e4e4e4,font-weight:bold,stroke-width:3px
+  class con3 focused
+  linkStyle 0,1 stroke:#5fd7d7,stroke-width:2px
+`
+
+	var writer bytes.Buffer
+	r := render.NewMermaid(io.Writer(&writer))
+	r.Render(&tree)
+
+	assert.Equal(t, want, writer.String())
+}
+
+func TestMermaidRendererUniqueIDs(t *testing.T) {
+	// the first output of the mock tree, without any id
+	tree := fetch.FakeTree()
+	tree.Root.Nodes = tree.Root.Nodes[:1]
+	var clearIDs func(node *i3.Node)
+	clearIDs = func(node *i3.Node) {
+		node.ID = 0
+		for _, n := range node.Nodes {
+			clearIDs(n)
+		}
+	}
+	clearIDs(tree.Root)
+
+	var writer bytes.Buffer
+	r := render.NewMermaid(io.Writer(&writer))
+	r.Render(tree)
+
+	got := writer.String()
+	assert.Contains(t, got, "  con0((\"[root] root\"))\n")
+	assert.Contains(t, got, "  con0 --> con0_1\n")
+	assert.Contains(t, got, "  con0_1 -->|output| con0_2\n")
+	assert.Contains(t, got, "  con0_21[\"[con] /bin/bash\"]\n")
+	assert.NotContains(t, got, "con0_22")
+}
@@ -0,0 +1,103 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/njhoffman/i3-tree/pkg/config"
+	"github.com/njhoffman/i3-tree/pkg/prune"
+	"go.i3wm.org/i3/v4"
+)
+
+// summarize describes the windows within nodes, grouped by class
+// or by title for windows without one, in the order they are found
+// e.g. "… 7 windows: Firefox×3, Alacritty×4"
+func summarize(nodes []*i3.Node, cfg *config.Config, au aurora.Aurora) string {
+	var order []string
+	counts := make(map[string]int)
+	windows, containers := 0, 0
+
+	var walk func(n *i3.Node)
+	walk = func(n *i3.Node) {
+		containers++
+
+		if prune.IsWindow(n) {
+			windows++
+
+			name := n.WindowProperties.Class
+			if name == "" {
+				name = n.Name
+			}
+			if counts[name] == 0 {
+				order = append(order, name)
+			}
+			counts[name]++
+		}
+
+		for _, c := range n.Nodes {
+			walk(c)
+		}
+		for _, c := range n.FloatingNodes {
+			walk(c)
+		}
+	}
+	for _, n := range nodes {
+		walk(n)
+	}
+
+	if windows == 0 {
+		return cfg.Formatting.Default.ApplyFormat(fmt.Sprintf("… %d %s", containers, plural(containers, "container")), au)
+	}
+
+	s := cfg.Formatting.Default.ApplyFormat(fmt.Sprintf("… %d %s", windows, plural(windows, "window")), au)
+
+	groups := make([]string, 0, len(order))
+	for _, name := range order {
+		group := cfg.Formatting.WindowClass.ApplyFormat(name, au)
+		if counts[name] > 1 {
+			group += fmt.Sprintf("×%d", counts[name])
+		}
+		groups = append(groups, group)
+	}
+
+	return s + ": " + strings.Join(groups, ", ")
+}
+
+// workspaceLevels tells how many levels below their workspace the nodes in one are
+// workspaces are at level 0, nodes outside of them are left out
+func workspaceLevels(root *i3.Node) map[*i3.Node]int {
+	levels := make(map[*i3.Node]int)
+
+	var walk func(n *i3.Node, level int)
+	walk = func(n *i3.Node, level int) {
+		if n == nil {
+			return
+		}
+		if n.Type == "workspace" {
+			level = 0
+		} else if level >= 0 {
+			level++
+		}
+		if level >= 0 {
+			levels[n] = level
+		}
+
+		for _, c := range n.Nodes {
+			walk(c, level)
+		}
+		for _, c := range n.FloatingNodes {
+			walk(c, level)
+		}
+	}
+	walk(root, -1)
+
+	return levels
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
@@ -0,0 +1,254 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/njhoffman/i3-tree/pkg/config"
+	"go.i3wm.org/i3/v4"
+)
+
+// SVG sizes, in pixels
+const (
+	svgFontSize   = 14
+	svgCharWidth  = 8.4
+	svgLineHeight = 18
+	svgPadding    = 20
+)
+
+// SVG colors used when the terminal default would be
+const (
+	svgBackground = "#1e1e1e"
+	svgForeground = "#e5e5e5"
+)
+
+// SVG draws the colored console output as an SVG image
+// It renders exactly what ColoredConsole prints,
+// so branches, colors and attributes come from the same config
+type SVG struct {
+	w      io.Writer
+	config *config.Config
+
+	// MaxDepth and Match are passed on to the console, see ColoredConsole
+	MaxDepth int
+	Match    Matcher
+}
+
+func NewSVG(w io.Writer) SVG {
+	return NewSVGWithConfig(w, config.DefaultConfig())
+}
+
+func NewSVGWithConfig(w io.Writer, cfg *config.Config) SVG {
+	return SVG{
+		w:      w,
+		config: cfg,
+	}
+}
+
+func (s SVG) Render(tree *i3.Tree) {
+	var buf bytes.Buffer
+	c := NewColoredConsoleWithConfig(&buf, s.config)
+	c.MaxDepth = s.MaxDepth
+	c.Match = s.Match
+	c.Render(tree)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if buf.Len() == 0 {
+		lines = nil
+	}
+
+	parsed := make([][]ansiRun, 0, len(lines))
+	columns := 0
+	for _, line := range lines {
+		runs := parseANSI(line)
+		parsed = append(parsed, runs)
+
+		width := 0
+		for _, r := range runs {
+			width += utf8.RuneCountInString(r.text)
+		}
+		if width > columns {
+			columns = width
+		}
+	}
+
+	width := float64(columns)*svgCharWidth + 2*svgPadding
+	height := len(parsed)*svgLineHeight + 2*svgPadding
+
+	fmt.Fprintf(s.w, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%d" viewBox="0 0 %s %d">`+"\n",
+		svgNum(width), height, svgNum(width), height)
+	fmt.Fprintf(s.w, `<rect width="100%%" height="100%%" rx="5" ry="5" fill="%s"/>`+"\n", svgBackground)
+	fmt.Fprintf(s.w, `<g font-family="monospace" font-size="%d" fill="%s" xml:space="preserve">`+"\n", svgFontSize, svgForeground)
+
+	for i, runs := range parsed {
+		s.printLine(runs, svgPadding+i*svgLineHeight)
+	}
+
+	fmt.Fprintln(s.w, "</g>")
+	fmt.Fprintln(s.w, "</svg>")
+}
+
+// printLine draws the backgrounds of a line and then its text
+// top is the y coordinate of the top of the line
+func (s SVG) printLine(runs []ansiRun, top int) {
+	col := 0
+	for _, r := range runs {
+		n := utf8.RuneCountInString(r.text)
+		if r.style.bg != "" {
+			fmt.Fprintf(s.w, `<rect x="%s" y="%d" width="%s" height="%d" fill="%s"/>`+"\n",
+				svgNum(svgPadding+float64(col)*svgCharWidth), top, svgNum(float64(n)*svgCharWidth), svgLineHeight, r.style.bg)
+		}
+		col += n
+	}
+
+	// text is drawn from its baseline
+	fmt.Fprintf(s.w, `<text x="%d" y="%d">`, svgPadding, top+svgFontSize)
+	for _, r := range runs {
+		attrs := r.style.svgAttrs()
+		if attrs == "" {
+			fmt.Fprint(s.w, html.EscapeString(r.text))
+			continue
+		}
+		fmt.Fprintf(s.w, "<tspan%s>%s</tspan>", attrs, html.EscapeString(r.text))
+	}
+	fmt.Fprintln(s.w, "</text>")
+}
+
+// svgNum formats a coordinate, rounded to avoid float noise
+func svgNum(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
+
+// ansiStyle is the state set by SGR escape sequences
+type ansiStyle struct {
+	fg        string
+	bg        string
+	bold      bool
+	dim       bool
+	italic    bool
+	underline bool
+}
+
+func (st ansiStyle) svgAttrs() string {
+	s := ""
+	if st.fg != "" {
+		s += ` fill="` + st.fg + `"`
+	}
+	if st.bold {
+		s += ` font-weight="bold"`
+	}
+	if st.italic {
+		s += ` font-style="italic"`
+	}
+	if st.underline {
+		s += ` text-decoration="underline"`
+	}
+	if st.dim {
+		s += ` opacity="0.6"`
+	}
+	return s
+}
+
+// ansiRun is a piece of text printed with the same style
+type ansiRun struct {
+	text  string
+	style ansiStyle
+}
+
+// parseANSI splits a line into runs of text sharing the same SGR style
+// other escape sequences are dropped
+func parseANSI(line string) []ansiRun {
+	var runs []ansiRun
+	var style ansiStyle
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() == 0 {
+			return
+		}
+		// merge with the previous run when nothing changed
+		if len(runs) > 0 && runs[len(runs)-1].style == style {
+			runs[len(runs)-1].text += text.String()
+		} else {
+			runs = append(runs, ansiRun{text.String(), style})
+		}
+		text.Reset()
+	}
+
+	for i := 0; i < len(line); i++ {
+		if line[i] != '\x1b' || i+1 >= len(line) || line[i+1] != '[' {
+			text.WriteByte(line[i])
+			continue
+		}
+
+		end := strings.IndexFunc(line[i+2:], func(r rune) bool {
+			return r >= 0x40 && r <= 0x7e
+		})
+		if end < 0 {
+			break
+		}
+		end += i + 2
+
+		if line[end] == 'm' {
+			flush()
+			style = applySGR(style, line[i+2:end])
+		}
+		i = end
+	}
+	flush()
+
+	return runs
+}
+
+// applySGR applies the parameters of a SGR sequence (e.g. "1;38;5;80") to a style
+func applySGR(st ansiStyle, params string) ansiStyle {
+	codes := strings.Split(params, ";")
+
+	for i := 0; i < len(codes); i++ {
+		code, err := strconv.Atoi(codes[i])
+		if err != nil {
+			code = 0
+		}
+
+		switch {
+		case code == 0:
+			st = ansiStyle{}
+		case code == 1:
+			st.bold = true
+		case code == 2:
+			st.dim = true
+		case code == 3:
+			st.italic = true
+		case code == 4:
+			st.underline = true
+		case code >= 30 && code <= 37:
+			st.fg = config.XtermHex(code - 30)
+		case code >= 90 && code <= 97:
+			st.fg = config.XtermHex(code - 90 + 8)
+		case code >= 40 && code <= 47:
+			st.bg = config.XtermHex(code - 40)
+		case code >= 100 && code <= 107:
+			st.bg = config.XtermHex(code - 100 + 8)
+		case code == 39:
+			st.fg = ""
+		case code == 49:
+			st.bg = ""
+		case (code == 38 || code == 48) && i+2 < len(codes) && codes[i+1] == "5":
+			index, _ := strconv.Atoi(codes[i+2])
+			if code == 38 {
+				st.fg = config.XtermHex(index)
+			} else {
+				st.bg = config.XtermHex(index)
+			}
+			i += 2
+		}
+	}
+
+	return st
+}
@@ -0,0 +1,76 @@
+package render_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/config"
+	"github.com/njhoffman/i3-tree/pkg/render"
+	"github.com/stretchr/testify/assert"
+	"go.i3wm.org/i3/v4"
+)
+
+func TestSVGRenderer(t *testing.T) {
+	tree := i3.Tree{
+		Root: &i3.Node{
+			ID:   1,
+			Name: "root",
+			Type: i3.NodeType(i3.Root),
+			Nodes: []*i3.Node{
+				{
+					ID:     2,
+					Name:   "1",
+					Type:   i3.NodeType(i3.WorkspaceNode),
+					Layout: i3.Layout(i3.SplitH),
+					Nodes: []*i3.Node{
+						{
+							ID:      3,
+							Name:    "a <b> & c",
+							Type:    i3.NodeType(i3.Con),
+							Focused: true,
+							WindowProperties: i3.WindowProperties{
+								Class: "Alacritty",
+							},
+						},
+						{
+							ID:    4,
+							Name:  "Slack",
+							Type:  i3.NodeType(i3.Con),
+							Marks: []string{"chat"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Formatting.Workspace.Background = 17
+	cfg.Formatting.WindowTitle.Attributes.Italic = true
+	cfg.Formatting.WindowMarks.Attributes.Underline = true
+	cfg.Formatting.WindowMarks.Attributes.Dim = true
+
+	var writer bytes.Buffer
+	r := render.NewSVGWithConfig(io.Writer(&writer), cfg)
+	r.Render(&tree)
+
+	got := writer.Bytes()
+	assert.NotContains(t, string(got), "\x1b")
+	assertGolden(t, "tree.svg", got)
+}
+
+func TestSVGRendererEmptyTree(t *testing.T) {
+	want := `<svg xmlns="http://www.w3.org/2000/svg" width="40" height="40" viewBox="0 0 40 40">
+<rect width="100%" height="100%" rx="5" ry="5" fill="#1e1e1e"/>
+<g font-family="monospace" font-size="14" fill="#e5e5e5" xml:space="preserve">
+</g>
+</svg>
+`
+
+	var writer bytes.Buffer
+	r := render.NewSVG(io.Writer(&writer))
+	r.Render(&i3.Tree{})
+
+	assert.Equal(t, want, writer.String())
+}
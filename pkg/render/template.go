@@ -0,0 +1,417 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"reflect"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/njhoffman/i3-tree/pkg/config"
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"go.i3wm.org/i3/v4"
+)
+
+// DefaultTemplate is used when no template is given
+// It looks like the monochromatic console
+const DefaultTemplate = `{{.Indent}}[{{.Type}}]{{if .Children}}{{with .Layout}}[{{.}}]{{end}}{{end}}` +
+	`{{with .Class}} ({{.}}){{end}}{{with .Name}} {{.}}{{end}}{{with .Marks}} [{{join . ", "}}]{{end}}`
+
+// Template renders every node as one line, using a text/template
+// executed with a TemplateNode
+// Nodes for which the template outputs nothing are skipped
+type Template struct {
+	w      io.Writer
+	config *config.Config
+	tmpl   *template.Template
+
+	// Extras fill in the fields go-i3 doesn't decode, when set
+	Extras fetch.Extras
+}
+
+// TemplateNode is the data a template is executed with
+type TemplateNode struct {
+	// Node is the raw i3 node, for anything not exposed below
+	Node *i3.Node
+
+	// Depth is 0 for the root of the rendered tree
+	Depth int
+	// Indent is the branches drawn before the node, as in the console
+	Indent string
+
+	ID       int64
+	Type     string
+	Layout   string
+	Name     string
+	Class    string
+	Instance string
+	Marks    []string
+	Rect     i3.Rect
+	// Children is the number of tiling and floating children
+	Children int
+
+	Focused bool
+	Urgent  bool
+	// true for floating containers and everything within them
+	Floating   bool
+	Fullscreen bool
+	Sticky     bool
+	// FocusedPath is true for the focused node and all its ancestors
+	FocusedPath bool
+
+	// PID, Visible and InhibitIdle are only known for sway windows
+	PID         int
+	Visible     bool
+	InhibitIdle bool
+	// ScratchpadState is fresh or changed for containers sent to the scratchpad, none otherwise
+	ScratchpadState string
+}
+
+func NewTemplate(w io.Writer, text string) (Template, error) {
+	return NewTemplateWithConfig(w, config.DefaultConfig(), text)
+}
+
+// NewTemplateWithConfig parses text, the config is used by the color helpers
+// Templates referencing unknown fields or functions are rejected
+func NewTemplateWithConfig(w io.Writer, cfg *config.Config, text string) (Template, error) {
+	return newTemplate(w, cfg, text, true)
+}
+
+// NewMonochromaticTemplateWithConfig is NewTemplateWithConfig with helpers that don't color anything
+func NewMonochromaticTemplateWithConfig(w io.Writer, cfg *config.Config, text string) (Template, error) {
+	return newTemplate(w, cfg, text, false)
+}
+
+func newTemplate(w io.Writer, cfg *config.Config, text string, colors bool) (Template, error) {
+	if text == "" {
+		text = DefaultTemplate
+	}
+
+	t := Template{
+		w:      w,
+		config: cfg,
+	}
+
+	tmpl, err := template.New("node").
+		Option("missingkey=error").
+		Funcs(t.funcs(aurora.NewAurora(colors))).
+		Parse(text)
+	if err != nil {
+		return Template{}, err
+	}
+
+	// fail now rather than in the middle of the tree
+	if err := t.check(tmpl.Tree.Root); err != nil {
+		return Template{}, err
+	}
+
+	t.tmpl = tmpl
+	return t, nil
+}
+
+// Render logs the error of a template failing on a node, see TryRender
+func (t Template) Render(tree *i3.Tree) {
+	if err := t.TryRender(tree); err != nil {
+		log.Print(err)
+	}
+}
+
+// TryRender stops at the first node the template fails on
+func (t Template) TryRender(tree *i3.Tree) error {
+	focusedPath := buildFocusedPath(tree.Root)
+	return t.print(tree.Root, "", "", 0, focusedPath, false)
+}
+
+func (t Template) print(node *i3.Node, prefix string, marker string, depth int, focusedPath map[i3.NodeID]bool, isFloating bool) error {
+	if node == nil {
+		return nil
+	}
+
+	isFloating = isFloating || node.Type == "floating_con"
+	extra := t.Extras[node.ID]
+
+	data := TemplateNode{
+		Node:            node,
+		Depth:           depth,
+		Indent:          prefix + marker,
+		ID:              int64(node.ID),
+		Type:            string(node.Type),
+		Layout:          string(node.Layout),
+		Name:            node.Name,
+		Class:           node.WindowProperties.Class,
+		Instance:        node.WindowProperties.Instance,
+		Marks:           node.Marks,
+		Rect:            node.Rect,
+		Children:        len(node.Nodes) + len(node.FloatingNodes),
+		Focused:         node.Focused,
+		Urgent:          node.Urgent,
+		Floating:        isFloating,
+		Fullscreen:      node.FullscreenMode != 0,
+		Sticky:          hasMark(node, "_sticky"),
+		FocusedPath:     focusedPath[node.ID],
+		PID:             extra.PID,
+		Visible:         extra.Visible != nil && *extra.Visible,
+		InhibitIdle:     extra.InhibitIdle,
+		ScratchpadState: extra.ScratchpadState,
+	}
+
+	var sb strings.Builder
+	if err := t.tmpl.Execute(&sb, data); err != nil {
+		return err
+	}
+	if line := sb.String(); line != "" {
+		fmt.Fprint(t.w, strings.TrimSuffix(line, "\n"), "\n")
+	}
+
+	// children of the root aren't indented, as in the console
+	if marker != "" {
+		if strings.HasPrefix(marker, t.config.Display.Branches.ConnectH) {
+			prefix += t.config.Display.Branches.Vertical + "  "
+		} else {
+			prefix += "   "
+		}
+	}
+
+	allNodes := append([]*i3.Node{}, node.Nodes...)
+	allNodes = append(allNodes, node.FloatingNodes...)
+
+	for i, n := range allNodes {
+		connector := t.config.Display.Branches.ConnectH
+		if i == len(allNodes)-1 {
+			connector = t.config.Display.Branches.ConnectV
+		}
+
+		childMarker := connector + t.config.Display.Branches.Horizontal
+		if err := t.print(n, prefix, childMarker, depth+1, focusedPath, isFloating); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// funcs are the helpers available in templates, on top of the text/template builtins
+func (t Template) funcs(au aurora.Aurora) template.FuncMap {
+	return template.FuncMap{
+		// format applies a formatting option by its config name, e.g. {{format "window_class" .Class}}
+		"format": func(name string, s string) (string, error) {
+			nf, ok := formattingOption(t.config, name)
+			if !ok {
+				return "", fmt.Errorf("unknown formatting option %q", name)
+			}
+			return nf.ApplyFormat(s, au), nil
+		},
+		// typeFormat applies the formatting of a node type, e.g. {{typeFormat .Type .Type}}
+		"typeFormat": func(nodeType string, s string) string {
+			if nf := nodeTypeFormat(t.config, i3.NodeType(nodeType)); nf != nil {
+				return nf.ApplyFormat(s, au)
+			}
+			return s
+		},
+		// icon is the configured icon, formatted, or "" when it's disabled
+		"icon": func(name string) (string, error) {
+			ic, ok := iconOption(t.config, name)
+			if !ok {
+				return "", fmt.Errorf("unknown icon %q", name)
+			}
+			if !ic.Enabled {
+				return "", nil
+			}
+			return ic.ApplyFormat(ic.Icon, au), nil
+		},
+		"fg": func(color int, s string) string {
+			return config.NodeFormat{Foreground: color}.ApplyFormat(s, au)
+		},
+		"bg": func(color int, s string) string {
+			return config.NodeFormat{Background: color}.ApplyFormat(s, au)
+		},
+		"bold": func(s string) string {
+			return config.NodeFormat{Attributes: config.Attributes{Bold: true}}.ApplyFormat(s, au)
+		},
+		"italic": func(s string) string {
+			return config.NodeFormat{Attributes: config.Attributes{Italic: true}}.ApplyFormat(s, au)
+		},
+		"underline": func(s string) string {
+			return config.NodeFormat{Attributes: config.Attributes{Underline: true}}.ApplyFormat(s, au)
+		},
+		"dim": func(s string) string {
+			return config.NodeFormat{Attributes: config.Attributes{Dim: true}}.ApplyFormat(s, au)
+		},
+		"join":  strings.Join,
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		// trunc shortens s to n characters, ending it with "..."
+		"trunc": func(n int, s string) string {
+			r := []rune(s)
+			if n < 4 || len(r) <= n {
+				return s
+			}
+			return string(r[:n-3]) + "..."
+		},
+	}
+}
+
+// formattingOption finds a config.FormattingOptions entry by its json name
+func formattingOption(cfg *config.Config, name string) (config.NodeFormat, bool) {
+	v := reflect.ValueOf(cfg.Formatting)
+	for i := 0; i < v.NumField(); i++ {
+		if v.Type().Field(i).Tag.Get("json") == name {
+			nf, ok := v.Field(i).Interface().(config.NodeFormat)
+			return nf, ok
+		}
+	}
+
+	return config.NodeFormat{}, false
+}
+
+// check rejects the fields of the TemplateNode a template uses that don't exist,
+// and the formatting options and icons it names that don't exist either
+// fields used where dot is something else, e.g. within with or range, are checked when executed
+func (t Template) check(node parse.Node) error {
+	data := reflect.TypeOf(TemplateNode{})
+
+	var walk func(node parse.Node, dot bool) error
+	walk = func(node parse.Node, dot bool) error {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return nil
+			}
+			for _, c := range n.Nodes {
+				if err := walk(c, dot); err != nil {
+					return err
+				}
+			}
+		case *parse.ActionNode:
+			return walk(n.Pipe, dot)
+		case *parse.IfNode:
+			return walkBranch(walk, &n.BranchNode, dot, dot)
+		case *parse.WithNode:
+			return walkBranch(walk, &n.BranchNode, dot, false)
+		case *parse.RangeNode:
+			return walkBranch(walk, &n.BranchNode, dot, false)
+		case *parse.TemplateNode:
+			if n.Pipe != nil {
+				return walk(n.Pipe, dot)
+			}
+		case *parse.PipeNode:
+			if n == nil {
+				return nil
+			}
+			for _, c := range n.Cmds {
+				if err := t.checkArgs(c); err != nil {
+					return err
+				}
+				for _, arg := range c.Args {
+					if err := walk(arg, dot); err != nil {
+						return err
+					}
+				}
+			}
+		case *parse.FieldNode:
+			if dot {
+				return checkField(data, n.Ident)
+			}
+		case *parse.VariableNode:
+			// $ is always the TemplateNode
+			if n.Ident[0] == "$" {
+				return checkField(data, n.Ident[1:])
+			}
+		}
+		return nil
+	}
+
+	return walk(node, true)
+}
+
+// checkArgs rejects calls to format and icon with an unknown name, e.g. {{icon "nope"}}
+func (t Template) checkArgs(c *parse.CommandNode) error {
+	if len(c.Args) < 2 {
+		return nil
+	}
+	fn, ok := c.Args[0].(*parse.IdentifierNode)
+	if !ok {
+		return nil
+	}
+	name, ok := c.Args[1].(*parse.StringNode)
+	if !ok {
+		return nil
+	}
+
+	switch fn.Ident {
+	case "format":
+		if _, ok := formattingOption(t.config, name.Text); !ok {
+			return fmt.Errorf("unknown formatting option %q", name.Text)
+		}
+	case "icon":
+		if _, ok := iconOption(t.config, name.Text); !ok {
+			return fmt.Errorf("unknown icon %q", name.Text)
+		}
+	}
+
+	return nil
+}
+
+// walkBranch checks the pipeline of if, with and range with the current dot,
+// and what they execute with dot, which is another value for with and range
+func walkBranch(walk func(parse.Node, bool) error, n *parse.BranchNode, dot bool, inner bool) error {
+	if err := walk(n.Pipe, dot); err != nil {
+		return err
+	}
+	if err := walk(n.List, inner); err != nil {
+		return err
+	}
+	return walk(n.ElseList, dot)
+}
+
+// checkField follows a chain of fields, e.g. .Rect.Width, as long as they are structs
+func checkField(t reflect.Type, idents []string) error {
+	for _, ident := range idents {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return nil
+		}
+
+		f, ok := t.FieldByName(ident)
+		if !ok {
+			if _, ok := reflect.PtrTo(t).MethodByName(ident); ok {
+				return nil
+			}
+			return fmt.Errorf("template: can't evaluate field %s in type %s", ident, t)
+		}
+		t = f.Type
+	}
+
+	return nil
+}
+
+// iconOption finds a config.IconOptions entry by its name
+func iconOption(cfg *config.Config, name string) (config.IconConfig, bool) {
+	switch name {
+	case "fullscreen":
+		return cfg.Icons.Fullscreen, true
+	case "floating":
+		return cfg.Icons.Floating, true
+	case "sticky":
+		return cfg.Icons.Sticky, true
+	case "urgent":
+		return cfg.Icons.Urgent, true
+	default:
+		return config.IconConfig{}, false
+	}
+}
+
+// hasMark tells if a node has the given mark
+func hasMark(node *i3.Node, mark string) bool {
+	for _, m := range node.Marks {
+		if m == mark {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,239 @@
+package render_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/config"
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/njhoffman/i3-tree/pkg/render"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.i3wm.org/i3/v4"
+)
+
+func templateTree() i3.Tree {
+	return i3.Tree{
+		Root: &i3.Node{
+			ID:   1,
+			Name: "root",
+			Type: i3.NodeType(i3.Root),
+			Nodes: []*i3.Node{
+				{
+					ID:     2,
+					Name:   "1",
+					Type:   i3.NodeType(i3.WorkspaceNode),
+					Layout: i3.Layout(i3.SplitH),
+					Nodes: []*i3.Node{
+						{
+							ID:      3,
+							Name:    "vim",
+							Type:    i3.NodeType(i3.Con),
+							Focused: true,
+							Marks:   []string{"a", "b"},
+							Rect:    i3.Rect{X: 0, Y: 0, Width: 960, Height: 1080},
+							WindowProperties: i3.WindowProperties{
+								Class:    "Alacritty",
+								Instance: "term",
+							},
+						},
+						{
+							ID:     4,
+							Type:   i3.NodeType(i3.Con),
+							Layout: i3.Layout(i3.SplitV),
+							Nodes: []*i3.Node{
+								{
+									ID:     5,
+									Name:   "Slack",
+									Type:   i3.NodeType(i3.Con),
+									Urgent: true,
+								},
+							},
+						},
+					},
+					FloatingNodes: []*i3.Node{
+						{
+							ID:   6,
+							Type: i3.NodeType(i3.FloatingCon),
+							Nodes: []*i3.Node{
+								{
+									ID:   7,
+									Name: "KeePassXC",
+									Type: i3.NodeType(i3.Con),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestTemplateRendererDefault(t *testing.T) {
+	tree := templateTree()
+
+	var writer bytes.Buffer
+	r, err := render.NewTemplate(io.Writer(&writer), "")
+	require.NoError(t, err)
+	r.Render(&tree)
+
+	want := `[root] root
+└──[workspace][splith] 1
+   ├──[con] (Alacritty) vim [a, b]
+   ├──[con][splitv]
+   │  └──[con] Slack
+   └──[floating_con]
+      └──[con] KeePassXC
+`
+	assert.Equal(t, want, writer.String())
+}
+
+func TestTemplateRendererData(t *testing.T) {
+	tree := templateTree()
+
+	var writer bytes.Buffer
+	r, err := render.NewTemplate(
+		io.Writer(&writer),
+		`{{if .Class}}{{.Depth}} {{.ID}} {{.Instance}} {{.Rect.Width}} {{.Focused}} {{.FocusedPath}}{{end}}`+
+			`{{if .Urgent}}{{.Name}} urgent{{end}}`+
+			`{{if and .Floating (not .Children)}}{{.Name}} floating{{end}}`,
+	)
+	require.NoError(t, err)
+	r.Render(&tree)
+
+	want := `2 3 term 960 true true
+Slack urgent
+KeePassXC floating
+`
+	assert.Equal(t, want, writer.String())
+}
+
+func TestTemplateRendererExtras(t *testing.T) {
+	tree := templateTree()
+
+	visible := true
+	var writer bytes.Buffer
+	r, err := render.NewTemplate(io.Writer(&writer), `{{if .PID}}{{.Name}} {{.PID}} {{.Visible}} {{.InhibitIdle}}{{end}}`)
+	require.NoError(t, err)
+	r.Extras = fetch.Extras{3: {PID: 4242, Visible: &visible, InhibitIdle: true}}
+	r.Render(&tree)
+
+	assert.Equal(t, "vim 4242 true true\n", writer.String())
+}
+
+func TestTemplateRendererFuncs(t *testing.T) {
+	tree := i3.Tree{
+		Root: &i3.Node{
+			ID:    1,
+			Name:  "a very long window title",
+			Type:  i3.NodeType(i3.Con),
+			Marks: []string{"x"},
+			WindowProperties: i3.WindowProperties{
+				Class: "Firefox",
+			},
+		},
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Formatting.WindowClass = config.NodeFormat{Foreground: 2}
+
+	var writer bytes.Buffer
+	r, err := render.NewTemplateWithConfig(
+		io.Writer(&writer),
+		cfg,
+		`{{format "window_class" .Class}} {{bold "b"}} {{upper .Class}} {{trunc 10 .Name}}`,
+	)
+	require.NoError(t, err)
+	r.Render(&tree)
+
+	assert.Equal(t, "\x1b[32mFirefox\x1b[0m \x1b[1mb\x1b[0m FIREFOX a very ...\n", writer.String())
+}
+
+func TestMonochromaticTemplateRendererFuncs(t *testing.T) {
+	tree := i3.Tree{
+		Root: &i3.Node{
+			ID:               1,
+			Type:             i3.NodeType(i3.Con),
+			WindowProperties: i3.WindowProperties{Class: "Firefox"},
+		},
+	}
+
+	var writer bytes.Buffer
+	r, err := render.NewMonochromaticTemplateWithConfig(
+		io.Writer(&writer),
+		config.DefaultConfig(),
+		`{{format "window_class" .Class}} {{fg 2 "g"}} {{bg 4 "b"}} {{bold "B"}} {{typeFormat .Type .Type}}`,
+	)
+	require.NoError(t, err)
+	r.Render(&tree)
+
+	assert.Equal(t, "Firefox g b B con\n", writer.String())
+}
+
+func TestTemplateRendererGuardedFields(t *testing.T) {
+	tree := i3.Tree{
+		Root: &i3.Node{
+			ID:   1,
+			Type: i3.NodeType(i3.Root),
+			Nodes: []*i3.Node{
+				{ID: 2, Type: i3.NodeType(i3.Con), Name: "vim", Marks: []string{"editor"}},
+			},
+		},
+	}
+
+	var writer bytes.Buffer
+	r, err := render.NewTemplate(io.Writer(&writer), `{{if .Marks}}{{.Name}} {{index .Marks 0}}{{end}}`)
+	require.NoError(t, err)
+	r.Render(&tree)
+
+	assert.Equal(t, "vim editor\n", writer.String())
+}
+
+func TestTemplateRendererExecError(t *testing.T) {
+	tree := i3.Tree{
+		Root: &i3.Node{
+			ID:   1,
+			Type: i3.NodeType(i3.Root),
+			Nodes: []*i3.Node{
+				{ID: 2, Type: i3.NodeType(i3.Con), Name: "vim", Marks: []string{"editor"}},
+				{ID: 3, Type: i3.NodeType(i3.Con), Name: "mpv"},
+			},
+		},
+	}
+
+	var writer bytes.Buffer
+	r, err := render.NewTemplate(io.Writer(&writer), `{{if ne .Type "root"}}{{.Name}} {{index .Marks 0}}{{end}}`)
+	require.NoError(t, err)
+
+	err = r.TryRender(&tree)
+
+	assert.Error(t, err)
+	assert.Equal(t, "vim editor\n", writer.String())
+}
+
+func TestTemplateRendererErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		text string
+	}{
+		{"syntax", "{{.Type"},
+		{"unknown field", "{{.Nope}}"},
+		{"unknown field in if", "{{if .Name}}{{.Nope}}{{end}}"},
+		{"unknown nested field", "{{.Rect.Nope}}"},
+		{"unknown field from $", "{{with .Class}}{{$.Nope}}{{end}}"},
+		{"unknown func", "{{nope .Type}}"},
+		{"unknown format", `{{format "nope" .Type}}`},
+		{"unknown icon", `{{icon "nope"}}`},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := render.NewTemplate(ioutil.Discard, tt.text)
+
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !freebsd && !openbsd && !netbsd && !dragonfly
+// +build !linux,!darwin,!freebsd,!openbsd,!netbsd,!dragonfly
+
+package term
+
+import (
+	"errors"
+	"os"
+)
+
+// MakeRaw puts the terminal f is connected to in raw mode
+// which isn't supported on this platform
+func MakeRaw(f *os.File) (restore func() error, err error) {
+	return nil, errors.New("raw terminal mode is not supported on this platform")
+}
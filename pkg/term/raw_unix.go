@@ -0,0 +1,50 @@
+//go:build linux || darwin || freebsd || openbsd || netbsd || dragonfly
+// +build linux darwin freebsd openbsd netbsd dragonfly
+
+package term
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// MakeRaw puts the terminal f is connected to in raw mode,
+// so keys are read one by one, unechoed, Ctrl-C included
+// restore puts it back as it was
+func MakeRaw(f *os.File) (restore func() error, err error) {
+	var old syscall.Termios
+	if err := termios(f, getTermios, &old); err != nil {
+		return nil, err
+	}
+
+	raw := old
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP |
+		syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+
+	if err := termios(f, setTermios, &raw); err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		return termios(f, setTermios, &old)
+	}, nil
+}
+
+func termios(f *os.File, request uintptr, t *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(
+		syscall.SYS_IOCTL,
+		f.Fd(),
+		request,
+		uintptr(unsafe.Pointer(t)),
+	)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
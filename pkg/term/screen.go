@@ -0,0 +1,116 @@
+package term
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ANSI sequences the Screen uses
+const (
+	enterAltScreen = "\x1b[?1049h"
+	exitAltScreen  = "\x1b[?1049l"
+	hideCursor     = "\x1b[?25l"
+	showCursor     = "\x1b[?25h"
+	disableWrap    = "\x1b[?7l"
+	enableWrap     = "\x1b[?7h"
+	clearScreen    = "\x1b[2J"
+	clearLine      = "\x1b[K"
+	clearBelow     = "\x1b[J"
+)
+
+// Screen draws frames in place on the terminal's alternate screen,
+// so the scrollback is left as it was
+// Only the lines that changed since the previous frame are written,
+// and lines too long for the terminal are cut rather than wrapped
+// It is not safe for concurrent use
+type Screen struct {
+	w io.Writer
+	// Height is the number of rows frames are cut to, 0 doesn't cut them
+	Height int
+
+	active bool
+	// lines on screen
+	lines []string
+}
+
+func NewScreen(w io.Writer, height int) *Screen {
+	return &Screen{
+		w:      w,
+		Height: height,
+	}
+}
+
+// Enter switches to the alternate screen and hides the cursor
+func (s *Screen) Enter() error {
+	if s.active {
+		return nil
+	}
+	s.active = true
+	s.lines = nil
+
+	_, err := io.WriteString(s.w, enterAltScreen+hideCursor+disableWrap+clearScreen)
+	return err
+}
+
+// Exit restores the terminal as it was before Enter
+// it can be called more than once, e.g. deferred and on a signal
+func (s *Screen) Exit() error {
+	if !s.active {
+		return nil
+	}
+	s.active = false
+
+	_, err := io.WriteString(s.w, enableWrap+showCursor+exitAltScreen)
+	return err
+}
+
+// Draw replaces what's on screen with frame
+func (s *Screen) Draw(frame string) error {
+	var lines []string
+	if frame != "" {
+		lines = strings.Split(strings.TrimSuffix(frame, "\n"), "\n")
+	}
+	if s.Height > 0 && len(lines) > s.Height {
+		lines = lines[:s.Height]
+	}
+
+	var buf bytes.Buffer
+	for i, line := range lines {
+		if i < len(s.lines) && s.lines[i] == line {
+			continue
+		}
+		buf.WriteString(moveTo(i) + line + clearLine)
+	}
+	if len(lines) < len(s.lines) {
+		buf.WriteString(moveTo(len(lines)) + clearBelow)
+	}
+	s.lines = lines
+
+	if buf.Len() == 0 {
+		return nil
+	}
+	_, err := s.w.Write(buf.Bytes())
+	return err
+}
+
+// Resize cuts the next frames to height rows
+// and clears the screen, as the terminal may have moved what was on it
+func (s *Screen) Resize(height int) error {
+	s.Height = height
+	return s.Clear()
+}
+
+// Clear empties the screen, the next frame is drawn whole
+func (s *Screen) Clear() error {
+	s.lines = nil
+
+	_, err := io.WriteString(s.w, clearScreen)
+	return err
+}
+
+// moveTo moves the cursor to the start of a line, 0 being the first one
+func moveTo(line int) string {
+	return fmt.Sprintf("\x1b[%d;1H", line+1)
+}
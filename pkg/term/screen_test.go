@@ -0,0 +1,66 @@
+package term_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/term"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScreenEnterExit(t *testing.T) {
+	var out bytes.Buffer
+	s := term.NewScreen(&out, 10)
+
+	assert.NoError(t, s.Enter())
+	assert.NoError(t, s.Enter())
+	assert.Equal(t, "\x1b[?1049h\x1b[?25l\x1b[?7l\x1b[2J", out.String())
+
+	out.Reset()
+	assert.NoError(t, s.Exit())
+	assert.NoError(t, s.Exit())
+	assert.Equal(t, "\x1b[?7h\x1b[?25h\x1b[?1049l", out.String())
+}
+
+func TestScreenDraw(t *testing.T) {
+	var out bytes.Buffer
+	s := term.NewScreen(&out, 10)
+
+	assert.NoError(t, s.Draw("one\ntwo\nthree\n"))
+	assert.Equal(t, "\x1b[1;1Hone\x1b[K\x1b[2;1Htwo\x1b[K\x1b[3;1Hthree\x1b[K", out.String())
+
+	// only the changed lines are written
+	out.Reset()
+	assert.NoError(t, s.Draw("one\n2\nthree\nfour\n"))
+	assert.Equal(t, "\x1b[2;1H2\x1b[K\x1b[4;1Hfour\x1b[K", out.String())
+
+	// nothing changed
+	out.Reset()
+	assert.NoError(t, s.Draw("one\n2\nthree\nfour\n"))
+	assert.Empty(t, out.String())
+
+	// what's left of the previous frame is cleared
+	out.Reset()
+	assert.NoError(t, s.Draw("one\n"))
+	assert.Equal(t, "\x1b[2;1H\x1b[J", out.String())
+}
+
+func TestScreenHeight(t *testing.T) {
+	var out bytes.Buffer
+	s := term.NewScreen(&out, 2)
+
+	assert.NoError(t, s.Draw("one\ntwo\nthree\n"))
+	assert.Equal(t, "\x1b[1;1Hone\x1b[K\x1b[2;1Htwo\x1b[K", out.String())
+}
+
+func TestScreenResize(t *testing.T) {
+	var out bytes.Buffer
+	s := term.NewScreen(&out, 2)
+	assert.NoError(t, s.Draw("one\ntwo\nthree\n"))
+
+	// the whole frame is drawn again
+	out.Reset()
+	assert.NoError(t, s.Resize(3))
+	assert.NoError(t, s.Draw("one\ntwo\nthree\n"))
+	assert.Equal(t, "\x1b[2J\x1b[1;1Hone\x1b[K\x1b[2;1Htwo\x1b[K\x1b[3;1Hthree\x1b[K", out.String())
+}
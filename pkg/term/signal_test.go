@@ -0,0 +1,41 @@
+package term_test
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/njhoffman/i3-tree/pkg/term"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifyStopSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGTERM can't be sent on windows")
+	}
+
+	ctx, stop := term.NotifyStop(context.Background())
+	defer stop()
+
+	p, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, p.Signal(syscall.SIGTERM))
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("SIGTERM didn't cancel the context")
+	}
+}
+
+func TestNotifyStopStop(t *testing.T) {
+	ctx, stop := term.NotifyStop(context.Background())
+	assert.NoError(t, ctx.Err())
+
+	stop()
+	assert.Equal(t, context.Canceled, ctx.Err())
+}
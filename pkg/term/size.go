@@ -0,0 +1,23 @@
+package term
+
+import (
+	"os"
+	"strconv"
+)
+
+// DefaultHeight is the number of rows assumed when the terminal can't tell
+const DefaultHeight = 24
+
+// Height returns the number of rows of the terminal f is connected to
+// falling back to $LINES, then to DefaultHeight
+func Height(f *os.File) int {
+	if _, rows, err := Size(f); err == nil && rows > 0 {
+		return rows
+	}
+
+	if rows, err := strconv.Atoi(os.Getenv("LINES")); err == nil && rows > 0 {
+		return rows
+	}
+
+	return DefaultHeight
+}
@@ -0,0 +1,19 @@
+//go:build !linux && !darwin && !freebsd && !openbsd && !netbsd && !dragonfly
+// +build !linux,!darwin,!freebsd,!openbsd,!netbsd,!dragonfly
+
+package term
+
+import (
+	"errors"
+	"os"
+)
+
+// Size returns the number of columns and rows of the terminal f is connected to
+// which isn't supported on this platform
+func Size(f *os.File) (cols int, rows int, err error) {
+	return 0, 0, errors.New("terminal size is not supported on this platform")
+}
+
+// NotifyResize relays the terminal size changes to c
+// which are never reported on this platform
+func NotifyResize(c chan<- os.Signal) {}
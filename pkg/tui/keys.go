@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Key is a key press, the character typed or the name of a special key
+type Key string
+
+const (
+	KeyUp        Key = "up"
+	KeyDown      Key = "down"
+	KeyLeft      Key = "left"
+	KeyRight     Key = "right"
+	KeyHome      Key = "home"
+	KeyEnd       Key = "end"
+	KeyPgUp      Key = "pgup"
+	KeyPgDown    Key = "pgdown"
+	KeyEnter     Key = "enter"
+	KeyEsc       Key = "esc"
+	KeyBackspace Key = "backspace"
+	KeyTab       Key = "tab"
+	KeyCtrlC     Key = "ctrl+c"
+)
+
+// escape sequences terminals send for special keys
+var sequences = map[string]Key{
+	"\x1b[A":  KeyUp,
+	"\x1bOA":  KeyUp,
+	"\x1b[B":  KeyDown,
+	"\x1bOB":  KeyDown,
+	"\x1b[C":  KeyRight,
+	"\x1bOC":  KeyRight,
+	"\x1b[D":  KeyLeft,
+	"\x1bOD":  KeyLeft,
+	"\x1b[H":  KeyHome,
+	"\x1bOH":  KeyHome,
+	"\x1b[1~": KeyHome,
+	"\x1b[F":  KeyEnd,
+	"\x1bOF":  KeyEnd,
+	"\x1b[4~": KeyEnd,
+	"\x1b[5~": KeyPgUp,
+	"\x1b[6~": KeyPgDown,
+}
+
+// ParseKeys splits what was read from a raw terminal into keys
+// unknown escape sequences and control characters are dropped
+func ParseKeys(b []byte) []Key {
+	var keys []Key
+
+	s := string(b)
+	for len(s) > 0 {
+		if s[0] == '\x1b' {
+			k, n := parseEscape(s)
+			if k != "" {
+				keys = append(keys, k)
+			}
+			s = s[n:]
+			continue
+		}
+
+		r, n := utf8.DecodeRuneInString(s)
+		s = s[n:]
+
+		switch {
+		case r == '\r' || r == '\n':
+			keys = append(keys, KeyEnter)
+		case r == '\t':
+			keys = append(keys, KeyTab)
+		case r == 0x7f || r == '\b':
+			keys = append(keys, KeyBackspace)
+		case r == 0x03:
+			keys = append(keys, KeyCtrlC)
+		case r == 0x04:
+			keys = append(keys, KeyPgDown)
+		case r == 0x15:
+			keys = append(keys, KeyPgUp)
+		case r < ' ' || r == utf8.RuneError:
+		default:
+			keys = append(keys, Key(string(r)))
+		}
+	}
+
+	return keys
+}
+
+// parseEscape returns the key s starts with and its length
+func parseEscape(s string) (Key, int) {
+	for seq, k := range sequences {
+		if strings.HasPrefix(s, seq) {
+			return k, len(seq)
+		}
+	}
+
+	if len(s) < 2 || s[1] != '[' {
+		return KeyEsc, 1
+	}
+
+	// skip an unknown CSI sequence up to its final byte
+	for i := 2; i < len(s); i++ {
+		if s[i] >= 0x40 && s[i] <= 0x7e {
+			return "", i + 1
+		}
+	}
+	return "", len(s)
+}
+
+// isText tells keys typed as text apart from special keys
+func isText(k Key) bool {
+	return utf8.RuneCountInString(string(k)) == 1
+}
@@ -0,0 +1,31 @@
+package tui_test
+
+import (
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/tui"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeys(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want []tui.Key
+	}{
+		{"text", "jk/é", []tui.Key{"j", "k", "/", "é"}},
+		{"arrows", "\x1b[A\x1b[B\x1bOC\x1b[D", []tui.Key{tui.KeyUp, tui.KeyDown, tui.KeyRight, tui.KeyLeft}},
+		{"pages", "\x1b[5~\x1b[6~\x15\x04", []tui.Key{tui.KeyPgUp, tui.KeyPgDown, tui.KeyPgUp, tui.KeyPgDown}},
+		{"home end", "\x1b[H\x1b[4~", []tui.Key{tui.KeyHome, tui.KeyEnd}},
+		{"controls", "\r\t\x7f\x03 ", []tui.Key{tui.KeyEnter, tui.KeyTab, tui.KeyBackspace, tui.KeyCtrlC, " "}},
+		{"esc", "\x1b", []tui.Key{tui.KeyEsc}},
+		{"esc then text", "\x1bq", []tui.Key{tui.KeyEsc, "q"}},
+		{"unknown sequence", "\x1b[15~j\x01", []tui.Key{"j"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tui.ParseKeys([]byte(tt.in)))
+		})
+	}
+}
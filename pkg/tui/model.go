@@ -0,0 +1,542 @@
+// Package tui browses the tree interactively and acts on the selected node
+// The Model holds the state and is driven by keys, Run connects it to a terminal
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/njhoffman/i3-tree/pkg/command"
+	"github.com/njhoffman/i3-tree/pkg/config"
+	"github.com/njhoffman/i3-tree/pkg/i3treeviewer"
+	"go.i3wm.org/i3/v4"
+)
+
+type mode int
+
+const (
+	browsing mode = iota
+	// typing the search query after /
+	searching
+	// typing what an action needs, e.g. the workspace to move to
+	prompting
+	// answering y or n before killing
+	confirming
+)
+
+// pending is an action waiting for its input or confirmation
+type pending struct {
+	label string
+	id    i3.NodeID
+	// build fails when the input can't be made into a command
+	build func(id i3.NodeID, input string) (string, error)
+}
+
+// row is a node shown on a line
+type row struct {
+	node     *i3.Node
+	branches string
+	depth    int
+	floating bool
+}
+
+// Model is the state of the tui: the pruned tree, the rows it's shown in,
+// the selected one and what is being typed
+type Model struct {
+	Config *config.Config
+	// Depth is the level below workspaces containers are collapsed at when first shown, as with prune.Depth
+	// 0 collapses nothing
+	Depth int
+
+	fetcher    i3treeviewer.Fetcher
+	pruner     i3treeviewer.Pruner
+	dispatcher command.Dispatcher
+	au         aurora.Aurora
+
+	tree      *i3.Tree
+	rows      []row
+	collapsed map[i3.NodeID]bool
+	// shown are the nodes laid out at least once, Depth only collapses the others
+	shown  map[i3.NodeID]bool
+	cursor int
+	// first row shown, and how many fit
+	offset int
+	height int
+
+	mode    mode
+	input   string
+	pending pending
+	// search is the last query, searchStart where the cursor was when it was started
+	search      string
+	searchStart i3.NodeID
+	status      string
+	quit        bool
+}
+
+func NewModel(
+	fetcher i3treeviewer.Fetcher,
+	pruner i3treeviewer.Pruner,
+	dispatcher command.Dispatcher,
+	au aurora.Aurora,
+) *Model {
+	return &Model{
+		Config:     config.DefaultConfig(),
+		fetcher:    fetcher,
+		pruner:     pruner,
+		dispatcher: dispatcher,
+		au:         au,
+		collapsed:  make(map[i3.NodeID]bool),
+		shown:      make(map[i3.NodeID]bool),
+	}
+}
+
+// Load fetches and prunes the tree, keeping the selected node selected
+// the focused one is selected the first time
+func (m *Model) Load() error {
+	tree, err := m.fetcher.Fetch()
+	if err != nil {
+		return err
+	}
+	pruned, err := i3treeviewer.TryPrune(m.pruner, &tree)
+	if err != nil {
+		return err
+	}
+
+	selected := m.Selected()
+	m.tree = pruned
+	m.layout()
+
+	if selected != nil && m.selectID(selected.ID) {
+		return nil
+	}
+	if selected == nil {
+		for i, r := range m.rows {
+			if r.node.Focused {
+				m.cursor = i
+				return nil
+			}
+		}
+	}
+	m.clampCursor()
+	return nil
+}
+
+// Selected returns the node under the cursor, nil when there's none
+func (m *Model) Selected() *i3.Node {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return nil
+	}
+	return m.rows[m.cursor].node
+}
+
+// Quit tells if the user asked to leave
+func (m *Model) Quit() bool {
+	return m.quit
+}
+
+// HandleKey updates the model with a key press
+func (m *Model) HandleKey(k Key) {
+	switch m.mode {
+	case searching:
+		m.handleSearch(k)
+	case prompting:
+		m.handlePrompt(k)
+	case confirming:
+		m.handleConfirm(k)
+	default:
+		m.handleBrowse(k)
+	}
+}
+
+func (m *Model) handleBrowse(k Key) {
+	m.status = ""
+
+	switch k {
+	case "q", KeyCtrlC:
+		m.quit = true
+	case "j", KeyDown:
+		m.move(1)
+	case "k", KeyUp:
+		m.move(-1)
+	case "g", KeyHome:
+		m.cursor = 0
+	case "G", KeyEnd:
+		m.cursor = len(m.rows) - 1
+	case KeyPgDown:
+		m.move(m.page())
+	case KeyPgUp:
+		m.move(-m.page())
+	case "h", KeyLeft:
+		m.collapseOrParent()
+	case "l", KeyRight:
+		m.expandOrChild()
+	case " ", KeyTab:
+		m.toggle()
+	case "/":
+		m.mode = searching
+		m.input = ""
+		if n := m.Selected(); n != nil {
+			m.searchStart = n.ID
+		}
+	case "n":
+		m.next(1, false)
+	case "N":
+		m.next(-1, false)
+	case "r":
+		if err := m.Load(); err != nil {
+			m.status = "error: " + err.Error()
+		}
+	case "f", KeyEnter:
+		if n := m.Selected(); n != nil {
+			m.run(command.Focus(n.ID))
+		}
+	case "t":
+		if n := m.Selected(); n != nil {
+			m.run(command.ToggleFloating(n.ID))
+		}
+	case "x":
+		m.ask(confirming, "kill "+m.plainLabel(), func(id i3.NodeID, _ string) (string, error) {
+			return command.Kill(id), nil
+		})
+	case "w":
+		m.ask(prompting, "move to workspace", infallible(command.MoveToWorkspace))
+	case "m":
+		m.ask(prompting, "mark", infallible(command.Mark))
+	case "L":
+		m.ask(prompting, "layout ("+strings.Join(command.Layouts, ", ")+")", command.Layout)
+	}
+}
+
+func (m *Model) handleSearch(k Key) {
+	switch k {
+	case KeyEsc, KeyCtrlC:
+		m.mode = browsing
+		m.selectID(m.searchStart)
+		return
+	case KeyEnter:
+		m.mode = browsing
+		return
+	case KeyBackspace:
+		m.input = dropLastRune(m.input)
+	default:
+		if !isText(k) {
+			return
+		}
+		m.input += string(k)
+	}
+
+	// jump to the first match as the query is typed
+	m.search = m.input
+	m.selectID(m.searchStart)
+	if m.search != "" {
+		m.next(1, true)
+	}
+}
+
+func (m *Model) handlePrompt(k Key) {
+	switch k {
+	case KeyEsc, KeyCtrlC:
+		m.mode = browsing
+	case KeyEnter:
+		m.mode = browsing
+		if m.input != "" {
+			m.build(m.input)
+		}
+	case KeyBackspace:
+		m.input = dropLastRune(m.input)
+	default:
+		if isText(k) {
+			m.input += string(k)
+		}
+	}
+}
+
+func (m *Model) handleConfirm(k Key) {
+	m.mode = browsing
+	if k == "y" || k == "Y" {
+		m.build("")
+	}
+}
+
+// ask waits for the input or confirmation of an action on the selected node
+func (m *Model) ask(md mode, label string, build func(id i3.NodeID, input string) (string, error)) {
+	n := m.Selected()
+	if n == nil {
+		return
+	}
+
+	m.mode = md
+	m.input = ""
+	m.pending = pending{label: label, id: n.ID, build: build}
+}
+
+// build runs the command of the pending action
+func (m *Model) build(input string) {
+	cmd, err := m.pending.build(m.pending.id, input)
+	if err != nil {
+		m.status = "error: " + err.Error()
+		return
+	}
+	m.run(cmd)
+}
+
+// infallible adapts a command builder that can't fail to an action builder
+func infallible(build func(id i3.NodeID, input string) string) func(i3.NodeID, string) (string, error) {
+	return func(id i3.NodeID, input string) (string, error) {
+		return build(id, input), nil
+	}
+}
+
+// run sends a command and reloads the tree it changed
+func (m *Model) run(cmd string) {
+	results, err := m.dispatcher.Run(cmd)
+	if err == nil {
+		err = command.Failed(cmd, results)
+	}
+	if err != nil {
+		m.status = "error: " + err.Error()
+		return
+	}
+	m.status = cmd
+
+	if err := m.Load(); err != nil {
+		m.status = "error: " + err.Error()
+	}
+}
+
+func (m *Model) move(delta int) {
+	m.cursor += delta
+	m.clampCursor()
+}
+
+func (m *Model) clampCursor() {
+	if m.cursor >= len(m.rows) {
+		m.cursor = len(m.rows) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// page is the number of rows a page up or down moves by
+func (m *Model) page() int {
+	if m.height > 2 {
+		return m.height - 2
+	}
+	return 1
+}
+
+func (m *Model) collapseOrParent() {
+	r, ok := m.selectedRow()
+	if !ok {
+		return
+	}
+	if hasChildren(r.node) && !m.collapsed[r.node.ID] {
+		m.collapsed[r.node.ID] = true
+		m.layout()
+		return
+	}
+
+	for i := m.cursor - 1; i >= 0; i-- {
+		if m.rows[i].depth < r.depth {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+func (m *Model) expandOrChild() {
+	r, ok := m.selectedRow()
+	if !ok || !hasChildren(r.node) {
+		return
+	}
+	if m.collapsed[r.node.ID] {
+		delete(m.collapsed, r.node.ID)
+		m.layout()
+		return
+	}
+	m.move(1)
+}
+
+func (m *Model) toggle() {
+	r, ok := m.selectedRow()
+	if !ok || !hasChildren(r.node) {
+		return
+	}
+	if m.collapsed[r.node.ID] {
+		delete(m.collapsed, r.node.ID)
+	} else {
+		m.collapsed[r.node.ID] = true
+	}
+	m.layout()
+}
+
+func (m *Model) selectedRow() (row, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return row{}, false
+	}
+	return m.rows[m.cursor], true
+}
+
+// selectID moves the cursor to a node, if it's shown
+func (m *Model) selectID(id i3.NodeID) bool {
+	for i, r := range m.rows {
+		if r.node.ID == id {
+			m.cursor = i
+			return true
+		}
+	}
+	return false
+}
+
+// next selects the next node matching the search in dir, wrapping around,
+// expanding the containers it's hidden in
+// the selected node is a candidate when inclusive
+func (m *Model) next(dir int, inclusive bool) {
+	if m.search == "" || m.tree == nil {
+		return
+	}
+
+	all := located(m.tree.Root, nil)
+	start := 0
+	if n := m.Selected(); n != nil {
+		for i, l := range all {
+			if l.node.ID == n.ID {
+				start = i
+				break
+			}
+		}
+	}
+	if !inclusive {
+		start += dir
+	}
+
+	for i := 0; i < len(all); i++ {
+		l := all[((start+dir*i)%len(all)+len(all))%len(all)]
+		if !matches(l.node, m.search) {
+			continue
+		}
+
+		for _, id := range l.ancestors {
+			delete(m.collapsed, id)
+		}
+		m.layout()
+		m.selectID(l.node.ID)
+		return
+	}
+
+	m.status = fmt.Sprintf("no match for %q", m.search)
+}
+
+// layout lays the rows out, children of collapsed containers left out
+func (m *Model) layout() {
+	m.rows = m.rows[:0]
+	if m.tree == nil || m.tree.Root == nil {
+		return
+	}
+
+	b := m.Config.Display.Branches
+	mid := b.ConnectH + b.Horizontal
+	last := b.ConnectV + b.Horizontal
+
+	// wsLevel is the level below the workspace n is in, -1 outside of workspaces
+	var walk func(n *i3.Node, indent string, marker string, depth int, wsLevel int, floating bool)
+	walk = func(n *i3.Node, indent string, marker string, depth int, wsLevel int, floating bool) {
+		m.rows = append(m.rows, row{node: n, branches: indent + marker, depth: depth, floating: floating})
+		if n.Type == "workspace" {
+			wsLevel = 0
+		}
+		if m.Depth > 0 && wsLevel >= m.Depth && !m.shown[n.ID] && hasChildren(n) {
+			m.collapsed[n.ID] = true
+		}
+		m.shown[n.ID] = true
+		if m.collapsed[n.ID] {
+			return
+		}
+
+		switch marker {
+		case "":
+		case last:
+			indent += strings.Repeat(" ", len([]rune(last)))
+		default:
+			indent += b.Vertical + strings.Repeat(" ", len([]rune(mid))-1)
+		}
+
+		all := children(n)
+		for i, c := range all {
+			marker := mid
+			if i == len(all)-1 {
+				marker = last
+			}
+			childLevel := wsLevel
+			if wsLevel >= 0 {
+				childLevel++
+			}
+			walk(c.node, indent, marker, depth+1, childLevel, floating || c.floating)
+		}
+	}
+	walk(m.tree.Root, "", "", 0, -1, false)
+
+	m.clampCursor()
+}
+
+type child struct {
+	node     *i3.Node
+	floating bool
+}
+
+// children are the tiling children of a node, followed by the floating ones
+func children(n *i3.Node) []child {
+	all := make([]child, 0, len(n.Nodes)+len(n.FloatingNodes))
+	for _, c := range n.Nodes {
+		all = append(all, child{c, false})
+	}
+	for _, c := range n.FloatingNodes {
+		all = append(all, child{c, true})
+	}
+	return all
+}
+
+func hasChildren(n *i3.Node) bool {
+	return len(n.Nodes) > 0 || len(n.FloatingNodes) > 0
+}
+
+// locatedNode is a node with the ids of the containers it's in
+type locatedNode struct {
+	node      *i3.Node
+	ancestors []i3.NodeID
+}
+
+// located lists the nodes of a tree in the order they are shown
+func located(n *i3.Node, ancestors []i3.NodeID) []locatedNode {
+	all := []locatedNode{{n, ancestors}}
+
+	inside := append(append([]i3.NodeID{}, ancestors...), n.ID)
+	for _, c := range children(n) {
+		all = append(all, located(c.node, inside)...)
+	}
+	return all
+}
+
+// matches tells if a node's name, class, instance or one of its marks contains query,
+// ignoring case
+func matches(n *i3.Node, query string) bool {
+	query = strings.ToLower(query)
+
+	fields := append([]string{n.Name, n.WindowProperties.Class, n.WindowProperties.Instance}, n.Marks...)
+	for _, f := range fields {
+		if strings.Contains(strings.ToLower(f), query) {
+			return true
+		}
+	}
+	return false
+}
+
+func dropLastRune(s string) string {
+	r := []rune(s)
+	if len(r) == 0 {
+		return s
+	}
+	return string(r[:len(r)-1])
+}
@@ -0,0 +1,290 @@
+package tui_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/njhoffman/i3-tree/pkg/command"
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/njhoffman/i3-tree/pkg/prune"
+	"github.com/njhoffman/i3-tree/pkg/tui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.i3wm.org/i3/v4"
+)
+
+func newModel(t *testing.T) (*tui.Model, *command.Recorder) {
+	rec := &command.Recorder{}
+	m := tui.NewModel(fetch.FromFake{}, &prune.NoOp{}, rec, aurora.NewAurora(false))
+	require.NoError(t, m.Load())
+	return m, rec
+}
+
+func press(m *tui.Model, keys ...tui.Key) {
+	for _, k := range keys {
+		m.HandleKey(k)
+	}
+}
+
+func typeText(m *tui.Model, s string) {
+	for _, r := range s {
+		m.HandleKey(tui.Key(string(r)))
+	}
+}
+
+func TestLoadSelectsFocused(t *testing.T) {
+	m, _ := newModel(t)
+
+	assert.Equal(t, i3.NodeID(26), m.Selected().ID)
+	assert.True(t, m.Selected().Focused)
+}
+
+func TestView(t *testing.T) {
+	m, _ := newModel(t)
+	press(m, "g", "j", "j")
+
+	lines := strings.Split(m.View(6), "\n")
+	assert.Equal(t, []string{
+		"  ▾ [root] root",
+		"  ├──▾ [output][output] HDMI-0",
+		"> │  ├──▾ [workspace][splith] 1",
+		"  │  │  └──  [con] Reddit.com - Mozilla Firefox",
+		"  │  ├──▾ [workspace][stacked] 2",
+	}, lines[:5])
+	assert.Contains(t, lines[5], "q quit")
+}
+
+func TestDepth(t *testing.T) {
+	m := tui.NewModel(fetch.FromFake{}, &prune.NoOp{}, &command.Recorder{}, aurora.NewAurora(false))
+	m.Depth = 1
+	require.NoError(t, m.Load())
+
+	lines := strings.Split(m.View(50), "\n")
+	ws5 := indexOf(lines, "  │  └──▾ [workspace][splith] 5")
+	require.NotEqual(t, -1, ws5)
+	assert.Equal(t, []string{
+		"  │     ├──▸ [con][splitv] … 2 windows",
+		"  │     └──▸ [con][splitv] … 2 windows",
+		"  └──▾ [output][output] HDMI-1",
+	}, lines[ws5+1:ws5+4])
+
+	// expanded containers stay so when reloading
+	press(m, "G")
+	for m.Selected().Name != "5" {
+		press(m, "k")
+	}
+	press(m, "j", " ", "r")
+	lines = strings.Split(m.View(50), "\n")
+	assert.Equal(t, "> │     ├──▾ [con][splitv]", lines[ws5+1])
+	assert.Equal(t, "  │     │  ├──  [con] /bin/bash", lines[ws5+2])
+}
+
+// indexOf returns the index of line in lines, -1 when it isn't there
+func indexOf(lines []string, line string) int {
+	for i, l := range lines {
+		if l == line {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestViewScrolls(t *testing.T) {
+	m, _ := newModel(t)
+
+	lines := strings.Split(m.View(4), "\n")
+	assert.Len(t, lines, 4)
+	assert.Equal(t, ">       │  └──  [con] VLC media player", lines[2])
+
+	press(m, "g")
+	lines = strings.Split(m.View(4), "\n")
+	assert.Equal(t, "> ▾ [root] root", lines[0])
+}
+
+func TestMove(t *testing.T) {
+	m, _ := newModel(t)
+
+	press(m, "g")
+	assert.Equal(t, i3.NodeID(1), m.Selected().ID)
+	press(m, "k")
+	assert.Equal(t, i3.NodeID(1), m.Selected().ID)
+	press(m, "j", tui.KeyDown)
+	assert.Equal(t, i3.NodeID(3), m.Selected().ID)
+	press(m, tui.KeyUp)
+	assert.Equal(t, i3.NodeID(2), m.Selected().ID)
+	press(m, "G")
+	assert.Equal(t, i3.NodeID(29), m.Selected().ID)
+	press(m, tui.KeyHome)
+	assert.Equal(t, i3.NodeID(1), m.Selected().ID)
+}
+
+func TestCollapseExpand(t *testing.T) {
+	m, _ := newModel(t)
+	press(m, "g", "j", "j")
+	assert.Equal(t, i3.NodeID(3), m.Selected().ID)
+
+	// collapsing workspace 1 hides its window
+	press(m, "h")
+	lines := strings.Split(m.View(10), "\n")
+	assert.Equal(t, "> │  ├──▸ [workspace][splith] 1 … 1 window", lines[2])
+	assert.Equal(t, "  │  ├──▾ [workspace][stacked] 2", lines[3])
+
+	// h again goes to the parent
+	press(m, "h")
+	assert.Equal(t, i3.NodeID(2), m.Selected().ID)
+
+	press(m, "j", "l")
+	assert.Equal(t, i3.NodeID(3), m.Selected().ID)
+	press(m, "l")
+	assert.Equal(t, i3.NodeID(4), m.Selected().ID)
+
+	// space toggles
+	press(m, "k", " ")
+	assert.Contains(t, m.View(10), "▸ [workspace][splith] 1")
+	press(m, tui.KeyTab)
+	assert.NotContains(t, m.View(10), "▸")
+}
+
+func TestSearch(t *testing.T) {
+	m, _ := newModel(t)
+
+	// the matches in collapsed containers are shown
+	press(m, "g", "j", "j", "j", "j", "j", "j", "j", "j", "j", "j", "j")
+	assert.Equal(t, i3.NodeID(12), m.Selected().ID)
+	press(m, "h", "g")
+
+	press(m, "/")
+	typeText(m, "sla")
+	assert.Equal(t, "/sla", lastLine(m.View(10)))
+	assert.Equal(t, i3.NodeID(15), m.Selected().ID)
+
+	press(m, tui.KeyEnter)
+	assert.Equal(t, i3.NodeID(15), m.Selected().ID)
+	assert.NotContains(t, m.View(40), "▸")
+
+	// n goes to the next match, wrapping around
+	press(m, "/")
+	typeText(m, "vlc")
+	press(m, tui.KeyEnter)
+	assert.Equal(t, i3.NodeID(26), m.Selected().ID)
+	press(m, "n")
+	assert.Equal(t, i3.NodeID(11), m.Selected().ID)
+	press(m, "n")
+	assert.Equal(t, i3.NodeID(14), m.Selected().ID)
+	press(m, "N", "N")
+	assert.Equal(t, i3.NodeID(26), m.Selected().ID)
+
+	// esc goes back to where the search started
+	press(m, "/")
+	typeText(m, "bash")
+	assert.Equal(t, i3.NodeID(28), m.Selected().ID)
+	press(m, tui.KeyEsc)
+	assert.Equal(t, i3.NodeID(26), m.Selected().ID)
+
+	press(m, "/")
+	typeText(m, "zoom")
+	press(m, tui.KeyEnter)
+	assert.Equal(t, `no match for "zoom"`, lastLine(m.View(10)))
+}
+
+func TestActions(t *testing.T) {
+	cases := []struct {
+		name string
+		keys []tui.Key
+		text string
+		want []string
+	}{
+		{"focus", []tui.Key{tui.KeyEnter}, "", []string{"[con_id=26] focus"}},
+		{"focus with f", []tui.Key{"f"}, "", []string{"[con_id=26] focus"}},
+		{"kill", []tui.Key{"x", "y"}, "", []string{"[con_id=26] kill"}},
+		{"kill cancelled", []tui.Key{"x", "n"}, "", nil},
+		{"toggle floating", []tui.Key{"t"}, "", []string{"[con_id=26] floating toggle"}},
+		{"move", []tui.Key{"w"}, "9: music", []string{`[con_id=26] move container to workspace "9: music"`}},
+		{"mark", []tui.Key{"m"}, "player", []string{`[con_id=26] mark --add "player"`}},
+		{"layout", []tui.Key{"L"}, "tabbed", []string{"[con_id=26] layout tabbed"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			m, rec := newModel(t)
+
+			press(m, tt.keys...)
+			if tt.text != "" {
+				typeText(m, tt.text)
+				press(m, tui.KeyEnter)
+			}
+
+			assert.Equal(t, tt.want, rec.Commands)
+			if tt.want != nil {
+				assert.Equal(t, tt.want[0], lastLine(m.View(10)))
+			}
+		})
+	}
+}
+
+func TestLayoutRejectsCommands(t *testing.T) {
+	m, rec := newModel(t)
+
+	press(m, "L")
+	typeText(m, "tabbed; kill")
+	press(m, tui.KeyEnter)
+
+	assert.Empty(t, rec.Commands)
+	assert.Equal(t, `error: unknown layout "tabbed; kill", available: splith, splitv, stacking, tabbed, toggle split`, lastLine(m.View(10)))
+}
+
+func TestPrompt(t *testing.T) {
+	m, rec := newModel(t)
+
+	press(m, "w")
+	typeText(m, "10")
+	assert.Equal(t, "move to workspace: 10", lastLine(m.View(10)))
+	press(m, tui.KeyBackspace)
+	assert.Equal(t, "move to workspace: 1", lastLine(m.View(10)))
+
+	// esc cancels, an empty input too
+	press(m, tui.KeyEsc)
+	press(m, "m", tui.KeyEnter)
+	assert.Empty(t, rec.Commands)
+
+	press(m, "x")
+	assert.Equal(t, "kill [con] VLC media player? [y/N]", lastLine(m.View(10)))
+}
+
+func TestActionErrors(t *testing.T) {
+	m, rec := newModel(t)
+
+	rec.Results = []i3.CommandResult{{Success: false, Error: "No such workspace"}}
+	press(m, "f")
+	assert.Equal(t, "error: [con_id=26] focus: No such workspace", lastLine(m.View(10)))
+
+	rec.Err = errors.New("connection refused")
+	press(m, "t")
+	assert.Equal(t, "error: connection refused", lastLine(m.View(10)))
+
+	// the next key clears the error
+	press(m, "j")
+	assert.Contains(t, lastLine(m.View(10)), "q quit")
+}
+
+func TestQuit(t *testing.T) {
+	m, _ := newModel(t)
+	assert.False(t, m.Quit())
+
+	// q is text while searching
+	press(m, "/", "q", tui.KeyEnter)
+	assert.False(t, m.Quit())
+
+	press(m, "q")
+	assert.True(t, m.Quit())
+
+	m, _ = newModel(t)
+	press(m, tui.KeyCtrlC)
+	assert.True(t, m.Quit())
+}
+
+func lastLine(s string) string {
+	return s[strings.LastIndex(s, "\n")+1:]
+}
@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/njhoffman/i3-tree/pkg/term"
+)
+
+// Run shows m on the terminal in and out are connected to, until q or Ctrl-C is pressed
+// the terminal is restored on the way out, SIGTERM included
+func Run(ctx context.Context, m *Model, in *os.File, out *os.File) error {
+	if err := m.Load(); err != nil {
+		return err
+	}
+
+	restore, err := term.MakeRaw(in)
+	if err != nil {
+		return fmt.Errorf("the tui needs a terminal: %w", err)
+	}
+	defer restore()
+
+	ctx, stop := term.NotifyStop(ctx)
+	defer stop()
+
+	screen := term.NewScreen(out, term.Height(out))
+	if err := screen.Enter(); err != nil {
+		return err
+	}
+	defer screen.Exit()
+
+	keys := make(chan []byte)
+	readErr := make(chan error, 1)
+	go readKeys(in, keys, readErr)
+
+	resized := make(chan os.Signal, 1)
+	term.NotifyResize(resized)
+	defer signal.Stop(resized)
+
+	for {
+		if err := screen.Draw(m.View(screen.Height)); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-readErr:
+			return err
+		case <-resized:
+			screen.Resize(term.Height(out))
+		case b := <-keys:
+			for _, k := range ParseKeys(b) {
+				m.HandleKey(k)
+			}
+			if m.Quit() {
+				return nil
+			}
+		}
+	}
+}
+
+// readKeys sends what is typed to keys until reading fails
+func readKeys(in io.Reader, keys chan<- []byte, readErr chan<- error) {
+	buf := make([]byte, 256)
+	for {
+		n, err := in.Read(buf)
+		if err != nil {
+			readErr <- err
+			return
+		}
+
+		b := make([]byte, n)
+		copy(b, buf[:n])
+		keys <- b
+	}
+}
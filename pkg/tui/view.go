@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/logrusorgru/aurora"
+	"go.i3wm.org/i3/v4"
+)
+
+const help = "j/k move  h/l collapse/expand  / search  enter focus  x kill  w move to workspace  " +
+	"t floating  m mark  L layout  r reload  q quit"
+
+// View draws the model in height lines, the last one being the status line
+// it scrolls to keep the cursor in sight
+func (m *Model) View(height int) string {
+	m.height = height
+
+	listHeight := height - 1
+	if listHeight < 1 {
+		listHeight = 1
+	}
+
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+listHeight {
+		m.offset = m.cursor - listHeight + 1
+	}
+	if max := len(m.rows) - listHeight; m.offset > max {
+		m.offset = max
+	}
+	if m.offset < 0 {
+		m.offset = 0
+	}
+
+	var b strings.Builder
+	shown := 0
+	for i := m.offset; i < len(m.rows) && shown < listHeight; i++ {
+		b.WriteString(m.line(i) + "\n")
+		shown++
+	}
+	// keep the status line at the bottom
+	b.WriteString(strings.Repeat("\n", listHeight-shown))
+	b.WriteString(m.statusLine())
+
+	return b.String()
+}
+
+// line draws a row, the selected one in reverse video
+func (m *Model) line(i int) string {
+	r := m.rows[i]
+	if i == m.cursor {
+		return m.au.Reverse("> " + r.branches + m.label(r, aurora.NewAurora(false))).String()
+	}
+	return "  " + m.au.Faint(r.branches).String() + m.label(r, m.au)
+}
+
+func (m *Model) statusLine() string {
+	switch m.mode {
+	case searching:
+		return "/" + m.input
+	case prompting:
+		return m.pending.label + ": " + m.input
+	case confirming:
+		return m.pending.label + "? [y/N]"
+	}
+
+	if m.status != "" {
+		return m.status
+	}
+	return m.au.Faint(help).String()
+}
+
+// plainLabel is the selected node's label, uncolored
+func (m *Model) plainLabel() string {
+	r, ok := m.selectedRow()
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(m.label(r, aurora.NewAurora(false)))
+}
+
+// label describes a node the way the console does, e.g. ▾ [con][splith] or   [con] (firefox) Jira
+// containers show ▾ when expanded and ▸ with the number of windows they hold when collapsed
+func (m *Model) label(r row, au aurora.Aurora) string {
+	n := r.node
+	cfg := m.Config
+	f := cfg.Formatting
+
+	expander := "  "
+	if hasChildren(n) {
+		expander = "▾ "
+		if m.collapsed[n.ID] {
+			expander = "▸ "
+		}
+	}
+
+	typeFormat := f.Con
+	switch {
+	case n.Type == "floating_con" || r.floating && n.Type == "con":
+		typeFormat = f.FloatCon
+	case n.Type == "workspace":
+		typeFormat = f.Workspace
+	case n.Type == "output":
+		typeFormat = f.Output
+	case n.Type == "root":
+		typeFormat = f.Root
+	}
+	s := expander + "[" + typeFormat.ApplyFormat(string(n.Type), au) + "]"
+	if n.Focused {
+		s = expander + f.FocusType.ApplyFormat("["+string(n.Type)+"]", au)
+	}
+
+	if hasChildren(n) && n.Layout != "" {
+		s += "[" + f.WindowLayout.ApplyFormat(string(n.Layout), au) + "]"
+	}
+
+	isMatch := m.search != "" && matches(n, m.search)
+	if cfg.Display.ShowWindowClass && n.WindowProperties.Class != "" {
+		classFormat := f.WindowClass
+		if isMatch {
+			classFormat = f.Match
+		}
+		s += " " + classFormat.ApplyFormat("("+n.WindowProperties.Class+")", au)
+	}
+	if n.Name != "" && (cfg.Display.ShowWindowTitles || n.Type != "con") {
+		titleFormat := f.WindowTitle
+		if isMatch {
+			titleFormat = f.Match
+		}
+		s += " " + titleFormat.ApplyFormat(n.Name, au)
+	}
+	if cfg.Display.ShowMarks && len(n.Marks) > 0 {
+		s += " " + f.WindowMarks.ApplyFormat("["+strings.Join(n.Marks, ", ")+"]", au)
+	}
+
+	if m.collapsed[n.ID] {
+		windows := countWindows(n)
+		word := "windows"
+		if windows == 1 {
+			word = "window"
+		}
+		s += " " + au.Faint(fmt.Sprintf("… %d %s", windows, word)).String()
+	}
+
+	return s
+}
+
+// countWindows counts the windows in a container
+func countWindows(n *i3.Node) int {
+	count := 0
+	for _, c := range children(n) {
+		if c.node.Type == "con" && !hasChildren(c.node) && (c.node.Window != 0 || c.node.Name != "") {
+			count++
+		}
+		count += countWindows(c.node)
+	}
+	return count
+}
@@ -0,0 +1,181 @@
+package watch
+
+import (
+	"fmt"
+
+	"github.com/njhoffman/i3-tree/pkg/diff"
+	"github.com/njhoffman/i3-tree/pkg/prune"
+	"go.i3wm.org/i3/v4"
+)
+
+// Action is what happened to a window or a workspace
+type Action string
+
+const (
+	CreatedWindow    Action = "created window"
+	ClosedWindow     Action = "closed window"
+	MovedWindow      Action = "moved window"
+	ResizedWindow    Action = "resized window"
+	AddedWorkspace   Action = "added workspace"
+	RemovedWorkspace Action = "removed workspace"
+	ChangedLayout    Action = "changed layout"
+)
+
+// Change is a human readable difference between two trees
+// e.g. moved window (firefox) Jira to workspace 3
+type Change struct {
+	Action Action
+	// Subject is the window or workspace the action is about
+	Subject string
+	// Detail completes the action, e.g. "to workspace 3", may be empty
+	Detail string
+}
+
+func (c Change) String() string {
+	s := string(c.Action) + " " + c.Subject
+	if c.Detail != "" {
+		s += " " + c.Detail
+	}
+	return s
+}
+
+// Changes lists what happened to windows and workspaces between prev and next
+// It summarizes the node by node differences of diff.Trees, ignoring focus and title changes
+// Removals come first, then the rest in the order of next
+func Changes(prev *i3.Tree, next *i3.Tree) []Change {
+	before := indexTree(prev)
+	after := indexTree(next)
+
+	var changes []Change
+	// windows already reported as moved, whose other changes don't matter
+	moved := make(map[i3.NodeID]bool)
+
+	for _, d := range diff.Trees(prev, next) {
+		switch d.Kind {
+		case diff.Removed:
+			b := before[d.Node.ID]
+			switch {
+			case b.node.Type == "workspace":
+				changes = append(changes, Change{RemovedWorkspace, b.node.Name, ""})
+			case prune.IsWindow(b.node):
+				changes = append(changes, Change{ClosedWindow, prune.WindowLabel(b.node), "from " + workspaceLabel(b.workspace)})
+			}
+
+		case diff.Added:
+			a := after[d.Node.ID]
+			switch {
+			case a.node.Type == "workspace":
+				changes = append(changes, Change{AddedWorkspace, a.node.Name, "on output " + a.output})
+			case prune.IsWindow(a.node):
+				changes = append(changes, Change{CreatedWindow, prune.WindowLabel(a.node), "on " + workspaceLabel(a.workspace)})
+			}
+
+		case diff.Moved:
+			a := after[d.Node.ID]
+			oldParent, _ := d.Old.(diff.NodeRef)
+			if a.node.Type == "workspace" || before[oldParent.ID].workspace == a.workspace {
+				continue
+			}
+			// the windows within a container follow it
+			for _, w := range windows(a.node) {
+				if !moved[w.ID] {
+					moved[w.ID] = true
+					changes = append(changes, Change{MovedWindow, prune.WindowLabel(w), "to " + workspaceLabel(a.workspace)})
+				}
+			}
+
+		case diff.RectChanged:
+			a := after[d.Node.ID]
+			b, _ := d.Old.(i3.Rect)
+			if !prune.IsWindow(a.node) || moved[d.Node.ID] || b.Width == a.node.Rect.Width && b.Height == a.node.Rect.Height {
+				continue
+			}
+			changes = append(changes, Change{ResizedWindow, prune.WindowLabel(a.node), fmt.Sprintf(
+				"%dx%d → %dx%d",
+				b.Width, b.Height,
+				a.node.Rect.Width, a.node.Rect.Height,
+			)})
+
+		case diff.LayoutChanged:
+			a := after[d.Node.ID]
+			b := before[d.Node.ID]
+			if prune.IsWindow(a.node) || b.node == nil || len(a.node.Nodes) == 0 || len(b.node.Nodes) == 0 {
+				continue
+			}
+			subject := "of container on " + workspaceLabel(a.workspace)
+			if a.node.Type == "workspace" {
+				subject = "of workspace " + a.node.Name
+			}
+			changes = append(changes, Change{ChangedLayout, subject, fmt.Sprintf("%v → %v", d.Old, d.New)})
+		}
+	}
+
+	return changes
+}
+
+// located is a node with the output and workspace it is on
+type located struct {
+	node      *i3.Node
+	output    string
+	workspace string
+}
+
+// indexTree locates the nodes of a tree by id
+func indexTree(tree *i3.Tree) map[i3.NodeID]located {
+	idx := make(map[i3.NodeID]located)
+	if tree == nil || tree.Root == nil {
+		return idx
+	}
+
+	var walk func(node *i3.Node, in located)
+	walk = func(node *i3.Node, in located) {
+		switch node.Type {
+		case "output":
+			in.output = node.Name
+		case "workspace":
+			in.workspace = node.Name
+		}
+		in.node = node
+
+		if _, seen := idx[node.ID]; !seen {
+			idx[node.ID] = in
+		}
+
+		for _, n := range node.Nodes {
+			walk(n, in)
+		}
+		for _, n := range node.FloatingNodes {
+			walk(n, in)
+		}
+	}
+	walk(tree.Root, located{})
+
+	return idx
+}
+
+// windows lists the windows of a subtree, node itself when it's one
+func windows(node *i3.Node) []*i3.Node {
+	if prune.IsWindow(node) {
+		return []*i3.Node{node}
+	}
+
+	var found []*i3.Node
+	for _, n := range node.Nodes {
+		found = append(found, windows(n)...)
+	}
+	for _, n := range node.FloatingNodes {
+		found = append(found, windows(n)...)
+	}
+	return found
+}
+
+func workspaceLabel(name string) string {
+	switch name {
+	case "":
+		return "no workspace"
+	case prune.ScratchWorkspace:
+		return "the scratchpad"
+	default:
+		return "workspace " + name
+	}
+}
@@ -0,0 +1,135 @@
+package watch_test
+
+import (
+	"testing"
+
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/njhoffman/i3-tree/pkg/watch"
+	"github.com/stretchr/testify/assert"
+	"go.i3wm.org/i3/v4"
+)
+
+func TestChangesNone(t *testing.T) {
+	prev, next := fetch.FakeTree(), fetch.FakeTree()
+
+	assert.Empty(t, watch.Changes(prev, next))
+}
+
+func TestChangesIgnoreFocus(t *testing.T) {
+	prev, next := fetch.FakeTree(), fetch.FakeTree()
+	ws6 := next.Root.Nodes[1].Nodes[0]
+	ws6.Nodes[0].Nodes[0].Focused = false
+	ws6.Nodes[1].Nodes[0].Focused = true
+	ws6.Focus = []i3.NodeID{ws6.Nodes[1].ID, ws6.Nodes[0].ID}
+
+	assert.Empty(t, watch.Changes(prev, next))
+}
+
+func TestChanges(t *testing.T) {
+	prev, next := fetch.FakeTree(), fetch.FakeTree()
+	hdmi0, hdmi1 := next.Root.Nodes[0], next.Root.Nodes[1]
+
+	// close Slack
+	ws4 := hdmi0.Nodes[3]
+	ws4.Nodes = ws4.Nodes[:2]
+
+	// move the VLC of workspace 3 to workspace 1
+	ws1, ws3 := hdmi0.Nodes[0], hdmi0.Nodes[2]
+	vlc := ws3.Nodes[1]
+	ws3.Nodes = ws3.Nodes[:1]
+	ws1.Nodes = append(ws1.Nodes, vlc)
+
+	// make the Firefox of workspace 3 take the whole screen
+	ws3.Nodes[0].Rect.Height = 1080
+
+	// remove workspace 5
+	hdmi0.Nodes = hdmi0.Nodes[:4]
+
+	// new workspace 7 with a terminal
+	hdmi1.Nodes = append(hdmi1.Nodes, &i3.Node{
+		ID:     100,
+		Name:   "7",
+		Type:   "workspace",
+		Layout: "tabbed",
+		Nodes: []*i3.Node{{
+			ID:               101,
+			Name:             "htop",
+			Type:             "con",
+			WindowProperties: i3.WindowProperties{Class: "Alacritty"},
+		}},
+	})
+
+	// workspace 2 goes from stacked to tabbed
+	hdmi0.Nodes[1].Layout = "tabbed"
+
+	want := []string{
+		"closed window Slack from workspace 4",
+		"removed workspace 5",
+		"closed window /bin/bash from workspace 5",
+		"closed window /bin/bash from workspace 5",
+		"closed window /bin/bash from workspace 5",
+		"closed window /bin/bash from workspace 5",
+		"moved window VLC media player to workspace 1",
+		"changed layout of workspace 2 stacked → tabbed",
+		"resized window Mozilla Firefox 1920x540 → 1920x1080",
+		"added workspace 7 on output HDMI-1",
+		"created window (Alacritty) htop on workspace 7",
+	}
+
+	var got []string
+	for _, c := range watch.Changes(prev, next) {
+		got = append(got, c.String())
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestChangesScratchpad(t *testing.T) {
+	prev, next := fetch.FakeTree(), fetch.FakeTree()
+	hdmi0 := next.Root.Nodes[0]
+	ws1 := hdmi0.Nodes[0]
+	firefox := ws1.Nodes[0]
+	ws1.Nodes = nil
+
+	next.Root.Nodes = append(next.Root.Nodes, &i3.Node{
+		ID:   200,
+		Name: "__i3",
+		Type: "output",
+		Nodes: []*i3.Node{{
+			ID:            201,
+			Name:          "__i3_scratch",
+			Type:          "workspace",
+			FloatingNodes: []*i3.Node{{ID: 202, Type: "floating_con", Nodes: []*i3.Node{firefox}}},
+		}},
+	})
+
+	got := watch.Changes(prev, next)
+
+	assert.Equal(t, []watch.Change{
+		{watch.AddedWorkspace, "__i3_scratch", "on output __i3"},
+		{watch.MovedWindow, "Reddit.com - Mozilla Firefox", "to the scratchpad"},
+	}, got)
+}
+
+func TestChangesMovedContainer(t *testing.T) {
+	prev, next := fetch.FakeTree(), fetch.FakeTree()
+	hdmi0 := next.Root.Nodes[0]
+	ws1, ws5 := hdmi0.Nodes[0], hdmi0.Nodes[4]
+	split := ws5.Nodes[0]
+	ws5.Nodes = ws5.Nodes[1:]
+	ws1.Nodes = append(ws1.Nodes, split)
+
+	assert.Equal(t, []watch.Change{
+		{watch.MovedWindow, "/bin/bash", "to workspace 1"},
+		{watch.MovedWindow, "/bin/bash", "to workspace 1"},
+	}, watch.Changes(prev, next))
+}
+
+func TestChangesEmptyTrees(t *testing.T) {
+	next := fetch.FakeTree()
+
+	got := watch.Changes(&i3.Tree{}, next)
+	assert.Len(t, got, 6+16)
+	assert.Equal(t, watch.Change{watch.AddedWorkspace, "1", "on output HDMI-0"}, got[0])
+
+	assert.Empty(t, watch.Changes(nil, &i3.Tree{}))
+}
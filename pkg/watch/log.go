@@ -0,0 +1,115 @@
+package watch
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/logrusorgru/aurora"
+	"go.i3wm.org/i3/v4"
+)
+
+// MaxLogEntries is the number of entries a Log keeps in memory
+// older ones are only in its file
+const MaxLogEntries = 500
+
+// LogEntry is a change and when it was seen
+type LogEntry struct {
+	Time time.Time
+	Change
+}
+
+func (e LogEntry) String() string {
+	return e.Time.Format("2006-01-02 15:04:05") + " " + e.Change.String()
+}
+
+// Log keeps the changes between the trees it's given, one after the other
+type Log struct {
+	// File gets every entry, timestamped, one per line, when set
+	File io.Writer
+	// Now is when the changes are seen
+	Now func() time.Time
+
+	prev    *i3.Tree
+	entries []LogEntry
+}
+
+func NewLog(file io.Writer) *Log {
+	return &Log{
+		File: file,
+		Now:  time.Now,
+	}
+}
+
+// Record logs what changed since the previous tree
+// the first tree is only remembered
+func (l *Log) Record(tree *i3.Tree) error {
+	prev := l.prev
+	l.prev = tree
+	if prev == nil {
+		return nil
+	}
+
+	now := l.Now()
+	for _, c := range Changes(prev, tree) {
+		e := LogEntry{Time: now, Change: c}
+		l.entries = append(l.entries, e)
+
+		if l.File != nil {
+			if _, err := fmt.Fprintln(l.File, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(l.entries) > MaxLogEntries {
+		l.entries = l.entries[len(l.entries)-MaxLogEntries:]
+	}
+
+	return nil
+}
+
+// Entries returns what was recorded, the oldest first
+func (l *Log) Entries() []LogEntry {
+	return l.entries
+}
+
+// Feed prints the latest entries fitting in lines, the oldest on top
+// with their action emphasized
+func (l *Log) Feed(w io.Writer, au aurora.Aurora, lines int) {
+	entries := l.entries
+	if lines <= 0 {
+		return
+	}
+	if len(entries) > lines {
+		entries = entries[len(entries)-lines:]
+	}
+
+	for _, e := range entries {
+		line := au.Faint(e.Time.Format("15:04:05")).String() + " " +
+			au.Bold(actionColor(au, e.Action)).String() + " " + e.Subject
+		if e.Detail != "" {
+			line += " " + au.Faint(e.Detail).String()
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+func actionColor(au aurora.Aurora, a Action) aurora.Value {
+	s := string(a)
+
+	switch a {
+	case CreatedWindow, AddedWorkspace:
+		return au.Green(s)
+	case ClosedWindow, RemovedWorkspace:
+		return au.Red(s)
+	case MovedWindow:
+		return au.Yellow(s)
+	case ResizedWindow:
+		return au.Cyan(s)
+	case ChangedLayout:
+		return au.Magenta(s)
+	default:
+		return au.Reset(s)
+	}
+}
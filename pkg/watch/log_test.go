@@ -0,0 +1,81 @@
+package watch_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/njhoffman/i3-tree/pkg/watch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLog(t *testing.T) {
+	var file bytes.Buffer
+	l := watch.NewLog(&file)
+	l.Now = func() time.Time { return time.Date(2026, 10, 17, 9, 30, 5, 0, time.UTC) }
+
+	prev, next := fetch.FakeTree(), fetch.FakeTree()
+	ws4 := next.Root.Nodes[0].Nodes[3]
+	ws4.Nodes = ws4.Nodes[:2]
+	next.Root.Nodes[0].Nodes[1].Layout = "tabbed"
+
+	// the first tree is only remembered
+	assert.NoError(t, l.Record(prev))
+	assert.Empty(t, l.Entries())
+
+	assert.NoError(t, l.Record(next))
+	assert.Len(t, l.Entries(), 2)
+	assert.Equal(t,
+		"2026-10-17 09:30:05 closed window Slack from workspace 4\n"+
+			"2026-10-17 09:30:05 changed layout of workspace 2 stacked → tabbed\n",
+		file.String(),
+	)
+
+	// no change, nothing logged
+	assert.NoError(t, l.Record(next))
+	assert.Len(t, l.Entries(), 2)
+
+	t.Run("feed", func(t *testing.T) {
+		var feed bytes.Buffer
+		l.Feed(&feed, aurora.NewAurora(false), 10)
+
+		assert.Equal(t,
+			"09:30:05 closed window Slack from workspace 4\n"+
+				"09:30:05 changed layout of workspace 2 stacked → tabbed\n",
+			feed.String(),
+		)
+	})
+
+	t.Run("feed keeps the latest entries fitting", func(t *testing.T) {
+		var feed bytes.Buffer
+		l.Feed(&feed, aurora.NewAurora(false), 1)
+
+		assert.Equal(t, "09:30:05 changed layout of workspace 2 stacked → tabbed\n", feed.String())
+
+		feed.Reset()
+		l.Feed(&feed, aurora.NewAurora(false), -3)
+		assert.Empty(t, feed.String())
+	})
+
+	t.Run("colored feed", func(t *testing.T) {
+		var feed bytes.Buffer
+		l.Feed(&feed, aurora.NewAurora(true), 1)
+
+		assert.Equal(t,
+			"\x1b[2m09:30:05\x1b[0m \x1b[1;35mchanged layout\x1b[0m of workspace 2 \x1b[2mstacked → tabbed\x1b[0m\n",
+			feed.String(),
+		)
+	})
+}
+
+func TestLogWithoutFile(t *testing.T) {
+	l := watch.NewLog(nil)
+	prev, next := fetch.FakeTree(), fetch.FakeTree()
+	next.Root.Nodes[0].Nodes[1].Layout = "tabbed"
+
+	assert.NoError(t, l.Record(prev))
+	assert.NoError(t, l.Record(next))
+	assert.Len(t, l.Entries(), 1)
+}
@@ -0,0 +1,178 @@
+package watch
+
+import (
+	"context"
+	"time"
+
+	"github.com/njhoffman/i3-tree/pkg/ipc"
+	"go.i3wm.org/i3/v4"
+)
+
+// DefaultDebounce is how long the watcher waits for a burst
+// of events to settle before redrawing
+const DefaultDebounce = 100 * time.Millisecond
+
+// RefreshFn redraws the tree once
+type RefreshFn func() error
+
+// EventSource notifies the watcher every time the tree may have changed
+// Next blocks until an event arrives and returns false once the source
+// is exhausted, after which Close reports why it stopped
+// *i3.EventReceiver satisfies this interface
+type EventSource interface {
+	Next() bool
+	Close() error
+}
+
+// SourceError is returned by Run when the event source stops
+// delivering events, so that callers can fall back to polling
+type SourceError struct {
+	Err error
+}
+
+func (e SourceError) Error() string {
+	if e.Err == nil {
+		return "event source closed"
+	}
+	return "event source failed: " + e.Err.Error()
+}
+
+func (e SourceError) Unwrap() error {
+	return e.Err
+}
+
+// ChanSource is an EventSource fed by a channel
+// every value sent is an event, closing the channel ends the source
+type ChanSource chan struct{}
+
+func (c ChanSource) Next() bool {
+	_, ok := <-c
+	return ok
+}
+
+func (c ChanSource) Close() error {
+	return nil
+}
+
+// SubscribeI3 subscribes to the i3 events that may change the tree
+func SubscribeI3() EventSource {
+	return i3.Subscribe(
+		i3.WindowEventType,
+		i3.WorkspaceEventType,
+		i3.OutputEventType,
+		i3.BindingEventType,
+	)
+}
+
+// SubscribeSway subscribes to the same events as SubscribeI3
+// on sway's IPC socket at path
+func SubscribeSway(path string) EventSource {
+	return ipc.SubscribeTo(
+		path,
+		string(i3.WindowEventType),
+		string(i3.WorkspaceEventType),
+		string(i3.OutputEventType),
+		string(i3.BindingEventType),
+	)
+}
+
+// Watcher redraws the tree whenever it may have changed
+type Watcher struct {
+	Refresh  RefreshFn
+	Debounce time.Duration
+}
+
+func NewWatcher(refresh RefreshFn) *Watcher {
+	return &Watcher{
+		Refresh:  refresh,
+		Debounce: DefaultDebounce,
+	}
+}
+
+// Run draws the tree and then redraws it every time src delivers events
+// A burst of events arriving within Debounce of each other triggers a single redraw
+// It returns a SourceError when src stops, a refresh error, or the context error
+func (w *Watcher) Run(ctx context.Context, src EventSource) error {
+	if err := w.Refresh(); err != nil {
+		src.Close()
+		return err
+	}
+
+	events := make(chan struct{})
+	done := make(chan error, 1)
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go func() {
+		for src.Next() {
+			select {
+			case events <- struct{}{}:
+			case <-stop:
+				return
+			}
+		}
+		done <- src.Close()
+	}()
+
+	timer := time.NewTimer(w.Debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	// only set while a redraw is pending
+	var pending <-chan time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			src.Close()
+			return ctx.Err()
+
+		case <-events:
+			if pending != nil && !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(w.Debounce)
+			pending = timer.C
+
+		case <-pending:
+			pending = nil
+			if err := w.Refresh(); err != nil {
+				src.Close()
+				return err
+			}
+
+		case err := <-done:
+			// don't lose the last burst
+			if pending != nil {
+				if err := w.Refresh(); err != nil {
+					return err
+				}
+			}
+			return SourceError{err}
+		}
+	}
+}
+
+// Poll redraws the tree every interval until the context is done
+func (w *Watcher) Poll(ctx context.Context, interval time.Duration) error {
+	if err := w.Refresh(); err != nil {
+		return err
+	}
+	return w.PollAfter(ctx, interval)
+}
+
+// PollAfter is Poll for a tree that was just drawn, e.g. by Run before its source failed
+// it waits interval before the first redraw
+func (w *Watcher) PollAfter(ctx context.Context, interval time.Duration) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+
+		if err := w.Refresh(); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package watch_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/njhoffman/i3-tree/pkg/fetch"
+	"github.com/njhoffman/i3-tree/pkg/i3treeviewer"
+	"github.com/njhoffman/i3-tree/pkg/prune"
+	"github.com/njhoffman/i3-tree/pkg/render"
+	"github.com/njhoffman/i3-tree/pkg/watch"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockRefresh renders the mock tree into a buffer and counts redraws
+func mockRefresh(buf *bytes.Buffer, count *int) watch.RefreshFn {
+	i3tv := i3treeviewer.NewI3TreeViewer(
+		fetch.FromFake{},
+		&prune.NoOp{},
+		render.NewMonochromaticConsole(buf),
+	)
+
+	return func() error {
+		*count++
+		return i3tv.View()
+	}
+}
+
+func TestWatcherDebouncesBursts(t *testing.T) {
+	var buf bytes.Buffer
+	count := 0
+
+	w := watch.NewWatcher(mockRefresh(&buf, &count))
+	// long enough that only the end of the source flushes the burst
+	w.Debounce = time.Hour
+
+	src := make(watch.ChanSource)
+	go func() {
+		for i := 0; i < 5; i++ {
+			src <- struct{}{}
+		}
+		close(src)
+	}()
+
+	err := w.Run(context.Background(), src)
+
+	assert.Equal(t, watch.SourceError{}, err)
+	// initial draw + a single redraw for the whole burst
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 2, strings.Count(buf.String(), "[root] root"))
+}
+
+func TestWatcherRedrawsAfterDebounce(t *testing.T) {
+	var buf bytes.Buffer
+	count := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	refresh := mockRefresh(&buf, &count)
+	w := watch.NewWatcher(func() error {
+		err := refresh()
+		if count == 2 {
+			cancel()
+		}
+		return err
+	})
+	w.Debounce = time.Millisecond
+
+	src := make(watch.ChanSource)
+	go func() {
+		src <- struct{}{}
+	}()
+
+	err := w.Run(ctx, src)
+
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestWatcherSourceFailure(t *testing.T) {
+	count := 0
+	w := watch.NewWatcher(func() error {
+		count++
+		return nil
+	})
+
+	failure := errors.New("no i3 socket")
+	err := w.Run(context.Background(), failingSource{failure})
+
+	var srcErr watch.SourceError
+	assert.True(t, errors.As(err, &srcErr))
+	assert.True(t, errors.Is(err, failure))
+	assert.Equal(t, 1, count)
+}
+
+func TestWatcherRefreshError(t *testing.T) {
+	failure := errors.New("fetch failed")
+	w := watch.NewWatcher(func() error {
+		return failure
+	})
+
+	err := w.Run(context.Background(), make(watch.ChanSource))
+
+	assert.Equal(t, failure, err)
+}
+
+func TestWatcherPoll(t *testing.T) {
+	var buf bytes.Buffer
+	count := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	refresh := mockRefresh(&buf, &count)
+	w := watch.NewWatcher(func() error {
+		err := refresh()
+		if count == 3 {
+			cancel()
+		}
+		return err
+	})
+
+	err := w.Poll(ctx, time.Millisecond)
+
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 3, count)
+}
+
+func TestWatcherPollAfter(t *testing.T) {
+	var buf bytes.Buffer
+	count := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	refresh := mockRefresh(&buf, &count)
+	w := watch.NewWatcher(func() error {
+		err := refresh()
+		if count == 2 {
+			cancel()
+		}
+		return err
+	})
+
+	err := w.PollAfter(ctx, time.Millisecond)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 2, count)
+
+	// the tree isn't redrawn before the interval
+	count = 0
+	err = w.PollAfter(ctx, time.Hour)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 0, count)
+}
+
+type failingSource struct {
+	err error
+}
+
+func (s failingSource) Next() bool {
+	return false
+}
+
+func (s failingSource) Close() error {
+	return s.err
+}